@@ -0,0 +1,291 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Keys of the oci cli rc file that hold the defaults of the oci cli options
+const (
+	rcConnectionTimeoutKey = "connection-timeout"
+	rcReadTimeoutKey       = "read-timeout"
+	rcMaxRetriesKey        = "max-retries"
+)
+
+// ClientSettings are the [oci-cli environment variables] that configure how a service is reached rather than
+// who is calling it
+//
+// [oci-cli environment variables]: https://docs.oracle.com/en-us/iaas/Content/API/SDKDocs/clienvironmentvariables.htm
+type ClientSettings struct {
+	// Endpoint replaces the host of the service, from OCI_CLI_ENDPOINT
+	Endpoint string
+	// RealmSpecificEndpoint enables the realm specific endpoint templates, from OCI_CLI_REALM_SPECIFIC_ENDPOINT.
+	// It is nil when the variable is not set, leaving the oci-go-sdk default in place.
+	RealmSpecificEndpoint *bool
+	// CertBundle is the path of a PEM file with the CA certificates to trust, from OCI_CLI_CERT_BUNDLE
+	CertBundle string
+
+	// ConnectionTimeout limits how long a connection may take to establish, from OCI_CLI_CONNECTION_TIMEOUT
+	// or connection-timeout in the oci cli rc file, in seconds
+	ConnectionTimeout time.Duration
+	// ReadTimeout limits how long to wait for the response headers, from OCI_CLI_READ_TIMEOUT or
+	// read-timeout in the oci cli rc file, in seconds
+	ReadTimeout time.Duration
+	// MaxRetries is the number of times a failed request is retried, from OCI_CLI_MAX_RETRIES or
+	// max-retries in the oci cli rc file. It is nil when neither is set, leaving the oci-go-sdk default in place.
+	MaxRetries *int
+
+	// Proxy holds the HTTP_PROXY, HTTPS_PROXY and NO_PROXY settings
+	Proxy httpproxy.Config
+}
+
+// ClientSettingsFromEnv reads the [ClientSettings] from the environment, or the source given with
+// [WithLookupEnv] or [WithEnvMap]. Defaults are read from the oci cli rc file in OCI_CLI_RC_FILE or
+// ~/.oci/oci_cli_rc, from the section named after the profile that [NewDefaultConfigProvider] would
+// choose with the same options, such as [WithProfile], or else from the DEFAULT section.
+func ClientSettingsFromEnv(opts ...DefaultOption) (ClientSettings, error) {
+	return clientSettingsFromEnv(newDefaultOptions(opts...))
+}
+
+func clientSettingsFromEnv(o *options) (ClientSettings, error) {
+	var s ClientSettings
+	s.Endpoint, _ = o.lookupEnv(EnvEndpoint)
+	s.CertBundle, _ = o.lookupEnv(EnvCertBundle)
+	if value, ok := o.lookupEnv(EnvRealmSpecificEndpoint); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return ClientSettings{}, fmt.Errorf("environment variable %s: %w", EnvRealmSpecificEndpoint, err)
+		}
+		s.RealmSpecificEndpoint = &enabled
+	}
+
+	resolution := resolveProfile(o)
+	rcFilePath := resolution.RcFilePath
+	rcDefaults := rcFileDefaults(rcFilePath, resolution.ProfileName)
+	setting := func(envVar, rcKey string) (int, bool, error) {
+		source := "environment variable " + envVar
+		value, ok := o.lookupEnv(envVar)
+		if !ok {
+			source = fmt.Sprintf("%s in %s", rcKey, rcFilePath)
+			value, ok = rcDefaults[rcKey]
+		}
+		if !ok {
+			return 0, false, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err == nil && n < 0 {
+			err = fmt.Errorf("%d is negative", n)
+		}
+		if err != nil {
+			return 0, false, fmt.Errorf("%s: %w", source, err)
+		}
+		return n, true, nil
+	}
+
+	if seconds, ok, err := setting(EnvConnectionTimeout, rcConnectionTimeoutKey); err != nil {
+		return ClientSettings{}, err
+	} else if ok {
+		s.ConnectionTimeout = time.Duration(seconds) * time.Second
+	}
+	if seconds, ok, err := setting(EnvReadTimeout, rcReadTimeoutKey); err != nil {
+		return ClientSettings{}, err
+	} else if ok {
+		s.ReadTimeout = time.Duration(seconds) * time.Second
+	}
+	if retries, ok, err := setting(EnvMaxRetries, rcMaxRetriesKey); err != nil {
+		return ClientSettings{}, err
+	} else if ok {
+		s.MaxRetries = &retries
+	}
+
+	s.Proxy = httpproxy.Config{
+		HTTPProxy:  firstEnv(o, "HTTP_PROXY", "http_proxy"),
+		HTTPSProxy: firstEnv(o, "HTTPS_PROXY", "https_proxy"),
+		NoProxy:    firstEnv(o, "NO_PROXY", "no_proxy"),
+	}
+	return s, nil
+}
+
+func firstEnv(o *options, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := o.lookupEnv(key); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Apply configures the client with the settings that are set.
+//
+// Service clients pick the realm specific endpoint template in their SetRegion, so call SetRegion after Apply
+// when RealmSpecificEndpoint is set. SetRegion replaces the host, which Endpoint also sets.
+func (s ClientSettings) Apply(client *common.BaseClient) error {
+	if s.RealmSpecificEndpoint != nil {
+		enabled := *s.RealmSpecificEndpoint
+		client.Configuration.RealmSpecificServiceEndpointTemplateEnabled = &enabled
+	}
+
+	if s.MaxRetries != nil {
+		policy := common.NoRetryPolicy()
+		if *s.MaxRetries > 0 {
+			policy = common.NewRetryPolicyWithOptions(common.WithMaximumNumberAttempts(uint(*s.MaxRetries) + 1))
+		}
+		client.Configuration.RetryPolicy = &policy
+	}
+
+	if s.configuresTransport() {
+		httpClient, err := s.httpClient(client.HTTPClient)
+		if err != nil {
+			return err
+		}
+		client.HTTPClient = httpClient
+	}
+
+	if s.Endpoint != "" {
+		client.Host = s.Endpoint
+	}
+	return nil
+}
+
+// ConfigureClient applies the [ClientSettings] from the environment to the client, for example
+//
+//	client, err := identity.NewIdentityClientWithConfigurationProvider(provider)
+//	...
+//	err = ocep.ConfigureClient(&client.BaseClient)
+func ConfigureClient(client *common.BaseClient, opts ...DefaultOption) error {
+	s, err := ClientSettingsFromEnv(opts...)
+	if err != nil {
+		return err
+	}
+	return s.Apply(client)
+}
+
+func (s ClientSettings) configuresTransport() bool {
+	return s.CertBundle != "" || s.ConnectionTimeout > 0 || s.ReadTimeout > 0 || s.Proxy != httpproxy.Config{}
+}
+
+// httpClient gives back a copy of dispatcher with a transport that uses the timeouts, proxies and CA
+// certificates of the settings. The transport of the dispatcher is cloned rather than replaced, so an
+// [common.OciHTTPTransportWrapper] keeps its TLS configuration and certificate refresh.
+func (s ClientSettings) httpClient(dispatcher common.HTTPRequestDispatcher) (*http.Client, error) {
+	var httpClient http.Client
+	switch c := dispatcher.(type) {
+	case nil:
+	case *http.Client:
+		if c != nil {
+			httpClient = *c
+		}
+	default:
+		return nil, fmt.Errorf("cannot configure the transport of a %T", dispatcher)
+	}
+
+	rootCAs, err := s.rootCAs()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := httpClient.Transport.(type) {
+	case nil:
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		s.configureTransport(transport, rootCAs)
+		httpClient.Transport = transport
+	case *http.Transport:
+		transport := t.Clone()
+		s.configureTransport(transport, rootCAs)
+		httpClient.Transport = transport
+	case *common.OciHTTPTransportWrapper:
+		template := t.TransportTemplate
+		httpClient.Transport = &common.OciHTTPTransportWrapper{
+			RefreshRate:       t.RefreshRate,
+			TLSConfigProvider: t.TLSConfigProvider,
+			TransportTemplate: func(tlsConfig *tls.Config) (http.RoundTripper, error) {
+				roundTripper, err := template.NewOrDefault(tlsConfig)
+				if err != nil {
+					return nil, err
+				}
+				transport, ok := roundTripper.(*http.Transport)
+				if !ok {
+					return nil, fmt.Errorf("cannot configure the transport of a %T", roundTripper)
+				}
+				transport = transport.Clone()
+				s.configureTransport(transport, rootCAs)
+				return transport, nil
+			},
+		}
+	default:
+		return nil, fmt.Errorf("cannot configure the transport of a %T", httpClient.Transport)
+	}
+	return &httpClient, nil
+}
+
+// configureTransport sets the timeouts, proxies and CA certificates of the settings that are set on
+// transport. The CA certificates replace the root CAs of its TLS configuration and keep the rest of it.
+func (s ClientSettings) configureTransport(transport *http.Transport, rootCAs *x509.CertPool) {
+	if s.Proxy != (httpproxy.Config{}) {
+		proxyFunc := s.Proxy.ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	if s.ConnectionTimeout > 0 {
+		dialer := &net.Dialer{Timeout: s.ConnectionTimeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = s.ConnectionTimeout
+	}
+	if s.ReadTimeout > 0 {
+		transport.ResponseHeaderTimeout = s.ReadTimeout
+	}
+
+	if rootCAs != nil {
+		tlsConfig := transport.TLSClientConfig.Clone()
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		tlsConfig.RootCAs = rootCAs
+		transport.TLSClientConfig = tlsConfig
+	}
+}
+
+// rootCAs reads the certificates of the cert bundle, or returns nil when it is not set
+func (s ClientSettings) rootCAs() (*x509.CertPool, error) {
+	if s.CertBundle == "" {
+		return nil, nil
+	}
+	bundle, err := os.ReadFile(internal.ExpandPath(s.CertBundle))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("no certificates found in %s from %s", s.CertBundle, envSource(EnvCertBundle))
+	}
+	return pool, nil
+}
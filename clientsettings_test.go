@@ -0,0 +1,327 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+var _ = Describe("ClientSettings", func() {
+	It("reads the settings from the environment", func() {
+		settings, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{
+			EnvEndpoint:              "https://identity.example.com",
+			EnvRealmSpecificEndpoint: "true",
+			EnvCertBundle:            "/etc/ssl/bundle.pem",
+		}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.Endpoint).To(Equal("https://identity.example.com"))
+		Expect(settings.RealmSpecificEndpoint).To(HaveValue(BeTrue()))
+		Expect(settings.CertBundle).To(Equal("/etc/ssl/bundle.pem"))
+	})
+
+	It("leaves unset settings empty", func() {
+		settings, err := ClientSettingsFromEnv(WithEnvMap(nil))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(ClientSettings{}))
+	})
+
+	It("rejects an invalid realm specific endpoint value", func() {
+		_, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{EnvRealmSpecificEndpoint: "sometimes"}))
+		Expect(err).To(MatchError(ContainSubstring(EnvRealmSpecificEndpoint)))
+	})
+
+	It("configures the endpoint and realm specific endpoint", func() {
+		client := common.BaseClient{Host: "https://identity.us-ashburn-1.oci.oraclecloud.com"}
+		Expect(ConfigureClient(&client, WithEnvMap(map[string]string{
+			EnvEndpoint:              "https://identity.example.com",
+			EnvRealmSpecificEndpoint: "false",
+		}))).To(Succeed())
+
+		Expect(client.Host).To(Equal("https://identity.example.com"))
+		Expect(client.IsOciRealmSpecificServiceEndpointTemplateEnabled()).To(BeFalse())
+		Expect(client.Configuration.RealmSpecificServiceEndpointTemplateEnabled).To(HaveValue(BeFalse()))
+	})
+
+	It("leaves the client alone when nothing is set", func() {
+		httpClient := &http.Client{}
+		client := common.BaseClient{Host: "https://identity.us-ashburn-1.oci.oraclecloud.com", HTTPClient: httpClient}
+		Expect(ConfigureClient(&client, WithEnvMap(nil))).To(Succeed())
+
+		Expect(client.Host).To(Equal("https://identity.us-ashburn-1.oci.oraclecloud.com"))
+		Expect(client.HTTPClient).To(BeIdenticalTo(httpClient))
+		Expect(client.Configuration.RealmSpecificServiceEndpointTemplateEnabled).To(BeNil())
+	})
+
+	Context("timeouts and retries", func() {
+		var rcFilePath string
+
+		BeforeEach(func() {
+			rcFilePath = createTempFile([]byte("[DEFAULT]\nconnection-timeout = 5\nread-timeout = 30\nmax-retries = 2\n"))
+			DeferCleanup(os.Remove, rcFilePath)
+		})
+
+		transport := func(client common.BaseClient) *http.Transport {
+			return client.HTTPClient.(*http.Client).Transport.(*http.Transport)
+		}
+
+		It("reads the defaults from the rc file", func() {
+			settings, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{EnvRcFile: rcFilePath}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settings.ConnectionTimeout).To(Equal(5 * time.Second))
+			Expect(settings.ReadTimeout).To(Equal(30 * time.Second))
+			Expect(settings.MaxRetries).To(HaveValue(Equal(2)))
+		})
+
+		It("reads the defaults of the profile from the rc file", func() {
+			profileRcFilePath := createTempFile([]byte("[OCI_CLI_SETTINGS]\ndefault_profile = dev\n\n[DEFAULT]\nread-timeout = 30\nmax-retries = 2\n\n[dev]\nmax-retries = 5\n\n[prod]\nmax-retries = 9\n"))
+			DeferCleanup(os.Remove, profileRcFilePath)
+
+			settings, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{EnvRcFile: profileRcFilePath}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settings.ReadTimeout).To(Equal(30 * time.Second))
+			Expect(settings.MaxRetries).To(HaveValue(Equal(5)))
+
+			settings, err = ClientSettingsFromEnv(WithEnvMap(map[string]string{EnvRcFile: profileRcFilePath, EnvProfile: "prod"}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settings.MaxRetries).To(HaveValue(Equal(9)))
+		})
+
+		It("reads the defaults of the profile given with WithProfile", func() {
+			profileRcFilePath := createTempFile([]byte("[DEFAULT]\nmax-retries = 2\n\n[prod]\nmax-retries = 9\n"))
+			DeferCleanup(os.Remove, profileRcFilePath)
+
+			settings, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{EnvRcFile: profileRcFilePath, EnvProfile: "dev"}), WithProfile("prod"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settings.MaxRetries).To(HaveValue(Equal(9)))
+
+			client := common.BaseClient{}
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{EnvRcFile: profileRcFilePath}), WithProfile("prod"))).To(Succeed())
+			Expect(client.Configuration.RetryPolicy.MaximumNumberAttempts).To(Equal(uint(10)))
+		})
+
+		It("prefers the environment over the rc file", func() {
+			settings, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{
+				EnvRcFile:            rcFilePath,
+				EnvConnectionTimeout: "7",
+				EnvReadTimeout:       "90",
+				EnvMaxRetries:        "0",
+			}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settings.ConnectionTimeout).To(Equal(7 * time.Second))
+			Expect(settings.ReadTimeout).To(Equal(90 * time.Second))
+			Expect(settings.MaxRetries).To(HaveValue(Equal(0)))
+		})
+
+		DescribeTable("rejects invalid values",
+			func(envVar, value string) {
+				_, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{envVar: value}))
+				Expect(err).To(MatchError(ContainSubstring(envVar)))
+			},
+			Entry("connection timeout", EnvConnectionTimeout, "soon"),
+			Entry("read timeout", EnvReadTimeout, "-1"),
+			Entry("max retries", EnvMaxRetries, "many"),
+		)
+
+		It("names the rc file for invalid defaults", func() {
+			badRcFilePath := createTempFile([]byte("[DEFAULT]\nmax-retries = many\n"))
+			DeferCleanup(os.Remove, badRcFilePath)
+
+			_, err := ClientSettingsFromEnv(WithEnvMap(map[string]string{EnvRcFile: badRcFilePath}))
+			Expect(err).To(MatchError(ContainSubstring("max-retries in " + badRcFilePath)))
+		})
+
+		It("configures the transport and retry policy", func() {
+			client := common.BaseClient{HTTPClient: &http.Client{Timeout: 42 * time.Second}}
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{EnvRcFile: rcFilePath}))).To(Succeed())
+
+			Expect(client.HTTPClient.(*http.Client).Timeout).To(Equal(42 * time.Second))
+			Expect(transport(client).TLSHandshakeTimeout).To(Equal(5 * time.Second))
+			Expect(transport(client).ResponseHeaderTimeout).To(Equal(30 * time.Second))
+			Expect(client.RetryPolicy()).ToNot(BeNil())
+			Expect(client.RetryPolicy().MaximumNumberAttempts).To(Equal(uint(3)))
+		})
+
+		It("disables retries", func() {
+			client := common.BaseClient{}
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{EnvMaxRetries: "0"}))).To(Succeed())
+			Expect(client.RetryPolicy().MaximumNumberAttempts).To(Equal(uint(1)))
+			Expect(client.HTTPClient).To(BeNil())
+		})
+	})
+
+	Context("the oci-go-sdk default client", func() {
+		var client common.BaseClient
+
+		BeforeEach(func() {
+			client = common.DefaultBaseClientWithSigner(nil)
+		})
+
+		delegate := func() *http.Transport {
+			wrapper := client.HTTPClient.(*http.Client).Transport.(*common.OciHTTPTransportWrapper)
+			Expect(wrapper.Refresh(true)).To(Succeed())
+			return wrapper.Delegate().(*http.Transport)
+		}
+
+		It("keeps the oci-go-sdk transport", func() {
+			defaultClient := client.HTTPClient.(*http.Client)
+			defaultWrapper := defaultClient.Transport.(*common.OciHTTPTransportWrapper)
+
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{
+				"HTTPS_PROXY":  "http://proxy.example.com:3128",
+				EnvReadTimeout: "30",
+			}))).To(Succeed())
+
+			Expect(client.HTTPClient.(*http.Client).Timeout).To(Equal(defaultClient.Timeout))
+			wrapper, ok := client.HTTPClient.(*http.Client).Transport.(*common.OciHTTPTransportWrapper)
+			Expect(ok).To(BeTrue())
+			Expect(wrapper).ToNot(BeIdenticalTo(defaultWrapper))
+			Expect(wrapper.TLSConfigProvider).To(BeIdenticalTo(defaultWrapper.TLSConfigProvider))
+			Expect(wrapper.RefreshRate).To(Equal(defaultWrapper.RefreshRate))
+
+			transport := delegate()
+			Expect(transport.ResponseHeaderTimeout).To(Equal(30 * time.Second))
+			req, _ := http.NewRequest(http.MethodGet, "https://identity.us-ashburn-1.oci.oraclecloud.com", nil)
+			Expect(transport.Proxy(req)).To(HaveField("Host", "proxy.example.com:3128"))
+		})
+
+		It("keeps the proxy of the transport when no proxy is set", func() {
+			proxyURL, _ := url.Parse("http://custom-proxy.example.com:8080")
+			custom := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+			client := common.BaseClient{HTTPClient: &http.Client{Transport: custom}}
+
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{EnvReadTimeout: "30"}))).To(Succeed())
+			transport := client.HTTPClient.(*http.Client).Transport.(*http.Transport)
+			Expect(transport.ResponseHeaderTimeout).To(Equal(30 * time.Second))
+			req, _ := http.NewRequest(http.MethodGet, "https://identity.us-ashburn-1.oci.oraclecloud.com", nil)
+			Expect(transport.Proxy(req)).To(Equal(proxyURL))
+		})
+
+		It("adds the cert bundle to the tls configuration", func() {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			DeferCleanup(server.Close)
+			bundlePath := createTempFile(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+			DeferCleanup(os.Remove, bundlePath)
+
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{EnvCertBundle: bundlePath}))).To(Succeed())
+			Expect(delegate().TLSClientConfig.RootCAs).ToNot(BeNil())
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := client.HTTPClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	It("rejects a dispatcher it cannot configure", func() {
+		dispatcher := dispatcherFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+		client := common.BaseClient{HTTPClient: dispatcher}
+		err := ConfigureClient(&client, WithEnvMap(map[string]string{EnvReadTimeout: "30"}))
+		Expect(err).To(MatchError(ContainSubstring("cannot configure")))
+		Expect(client.HTTPClient).ToNot(BeAssignableToTypeOf(&http.Client{}))
+	})
+
+	It("sends requests through the proxy", func() {
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		DeferCleanup(proxy.Close)
+
+		client := common.BaseClient{}
+		Expect(ConfigureClient(&client, WithEnvMap(map[string]string{"HTTP_PROXY": proxy.URL}))).To(Succeed())
+
+		req, _ := http.NewRequest(http.MethodGet, "http://identity.example.com/20160918/users", nil)
+		resp, err := client.HTTPClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(proxied).To(Equal("http://identity.example.com/20160918/users"))
+	})
+
+	Context("cert bundle", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		It("trusts the certificates in the bundle", func() {
+			bundlePath := createTempFile(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+			DeferCleanup(os.Remove, bundlePath)
+
+			client := common.BaseClient{HTTPClient: &http.Client{Timeout: 42 * time.Second}}
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{EnvCertBundle: bundlePath}))).To(Succeed())
+			Expect(client.HTTPClient.(*http.Client).Timeout).To(Equal(42 * time.Second))
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := client.HTTPClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+		})
+
+		It("does not trust other certificates", func() {
+			template := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+			otherCert, err := x509.CreateCertificate(rand.Reader, template, template, &testPk.PublicKey, testPk)
+			Expect(err).ToNot(HaveOccurred())
+			bundlePath := createTempFile(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherCert}))
+			DeferCleanup(os.Remove, bundlePath)
+
+			client := common.BaseClient{}
+			Expect(ConfigureClient(&client, WithEnvMap(map[string]string{EnvCertBundle: bundlePath}))).To(Succeed())
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			_, err = client.HTTPClient.Do(req)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects a bundle without certificates", func() {
+			bundlePath := createTempFile([]byte("not a certificate"))
+			DeferCleanup(os.Remove, bundlePath)
+
+			client := common.BaseClient{}
+			err := ConfigureClient(&client, WithEnvMap(map[string]string{EnvCertBundle: bundlePath}))
+			Expect(err).To(MatchError(ContainSubstring(EnvCertBundle)))
+			Expect(client.HTTPClient).To(BeNil())
+		})
+	})
+})
+
+type dispatcherFunc func(*http.Request) (*http.Response, error)
+
+func (f dispatcherFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
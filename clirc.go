@@ -0,0 +1,64 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"errors"
+	"io/fs"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	defaultConfigFilePath = "~/.oci/config"
+	defaultRcFilePath     = "~/.oci/oci_cli_rc"
+
+	cliSettingsSection = "OCI_CLI_SETTINGS"
+	defaultProfileKey  = "default_profile"
+)
+
+// defaultProfileFromFile returns the default_profile from the OCI_CLI_SETTINGS section of the
+// oci cli rc or config file, or "" when it is not set or the file does not exist
+func defaultProfileFromFile(filePath string) (string, error) {
+	cliConf, err := ini.Load(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return cliConf.Section(cliSettingsSection).Key(defaultProfileKey).String(), nil
+}
+
+// rcFileDefaults returns the keys of the DEFAULT section of the oci cli rc file, overridden by the keys of
+// the section named after the profile, or nil when the file cannot be loaded
+func rcFileDefaults(filePath, profileName string) map[string]string {
+	rcConf, err := ini.Load(filePath)
+	if err != nil {
+		return nil
+	}
+	defaults := rcConf.Section(ini.DefaultSection).KeysHash()
+	if profileName == "" || profileName == ini.DefaultSection {
+		return defaults
+	}
+	if section, err := rcConf.GetSection(profileName); err == nil {
+		for key, value := range section.KeysHash() {
+			defaults[key] = value
+		}
+	}
+	return defaults
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Command ocep prints the OCI configuration that [ocep.NewDefaultConfigProvider] resolves from the
+oci cli environment variables and config files, along with the config file and profile it chose
+and the source of each value.
+
+It never makes network calls, so principal based authentication (OCI_CLI_AUTH=instance_principal
+etc.) is only reported, not resolved.
+
+Usage:
+
+	ocep [-config-file path] [-profile name]
+
+The exit status is 1 when the configuration is not valid, and 3 when it uses principal based
+authentication and could not be checked.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ontariosystems/oci-cli-env-provider"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// optionSource is the source [ocep.Resolution] reports for values set by options
+const optionSource = "option"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ocep", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config-file", "", "config file, overrides "+ocep.EnvConfigFile)
+	profile := flags.String("profile", "", "config file profile, overrides "+ocep.EnvProfile)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var opts []ocep.DefaultOption
+	if *configFile != "" {
+		opts = append(opts, ocep.WithConfigFile(*configFile))
+	}
+	if *profile != "" {
+		opts = append(opts, ocep.WithProfile(*profile))
+	}
+
+	provider := ocep.NewDefaultConfigProvider(opts...)
+	if resolver, ok := provider.(ocep.Resolver); ok {
+		printResolution(stdout, flagSources(resolver.Resolution()))
+	}
+
+	switch authType := common.AuthenticationType(os.Getenv(ocep.EnvAuth)); authType {
+	case ocep.InstancePrincipalType, ocep.ResourcePrincipalType, ocep.OkeWorkloadIdentityType, ocep.InstanceOboUserType:
+		printRuntimeSources(stdout, provider)
+		_, _ = fmt.Fprintf(stdout, "\n%s=%s: credentials are fetched at runtime and cannot be checked offline\n", ocep.EnvAuth, authType)
+		return 3
+	}
+
+	printExplanation(stdout, ocep.Explain(provider))
+
+	if valid, err := common.IsConfigurationProviderValid(provider); !valid {
+		_, _ = fmt.Fprintf(stdout, "\nconfiguration is not valid: %v\n", err)
+		return 1
+	}
+
+	var mismatchErr *ocep.FingerprintMismatchError
+	if err := ocep.VerifyFingerprint(provider); errors.As(err, &mismatchErr) {
+		_, _ = fmt.Fprintf(stdout, "\nconfiguration is not valid: %v\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprintln(stdout, "\nconfiguration is valid")
+	return 0
+}
+
+// flagSources names the flags as the source of the values they set
+func flagSources(resolution ocep.Resolution) ocep.Resolution {
+	if resolution.ConfigFileSource == optionSource {
+		resolution.ConfigFileSource = "flag -config-file"
+	}
+	if resolution.ProfileSource == optionSource {
+		resolution.ProfileSource = "flag -profile"
+	}
+	return resolution
+}
+
+func printResolution(w io.Writer, resolution ocep.Resolution) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "config file:\t%s\t(%s)\n", resolution.ConfigFilePath, resolution.ConfigFileSource)
+	_, _ = fmt.Fprintf(tw, "rc file:\t%s\t(%s)\n", resolution.RcFilePath, resolution.RcFileSource)
+	_, _ = fmt.Fprintf(tw, "profile:\t%s\t(%s)\n", resolution.ProfileName, resolution.ProfileSource)
+	_ = tw.Flush()
+
+	if resolution.Err != nil {
+		_, _ = fmt.Fprintf(w, "\n%v\n", resolution.Err)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// printRuntimeSources lists the source of each field without resolving it, which would make network calls
+func printRuntimeSources(w io.Writer, provider common.ConfigurationProvider) {
+	describer, _ := provider.(ocep.SourceDescriber)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "FIELD\tVALUE\tSOURCE")
+	for _, field := range ocep.Fields {
+		source := ""
+		if describer != nil {
+			source = describer.DescribeSource(field)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", field, "(fetched at runtime)", source)
+	}
+	_ = tw.Flush()
+}
+
+func printExplanation(w io.Writer, explanation ocep.Explanation) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "FIELD\tVALUE\tSOURCE")
+	for _, fe := range explanation {
+		if fe.Field == ocep.FieldKeyID {
+			// the key id of a security token contains the token itself
+			continue
+		}
+
+		switch {
+		case fe.Found() && fe.Field == ocep.FieldPrivateKey:
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", fe.Field, "(loaded)", fe.Source)
+		case fe.Found():
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", fe.Field, fe.Value, fe.Source)
+		default:
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", fe.Field, "(missing)", "")
+		}
+	}
+	_ = tw.Flush()
+
+	for _, fe := range explanation {
+		if fe.Found() || fe.Field == ocep.FieldKeyID {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "\n%s could not be resolved:\n", fe.Field)
+		for _, skipped := range fe.Skipped {
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", skipped.Source, describeError(skipped.Err))
+		}
+	}
+}
+
+// describeError lists the missing environment variables of err, or else the error itself
+func describeError(err error) string {
+	var missing []string
+	for _, e := range flattenErrors(err) {
+		var envErr *ocep.EnvError
+		if errors.As(e, &envErr) {
+			missing = append(missing, envErr.EnvVar)
+		}
+	}
+
+	if len(missing) == 0 {
+		return err.Error()
+	}
+	return strings.Join(missing, ", ") + " not set"
+}
+
+func flattenErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, flattenErrors(e)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ontariosystems/oci-cli-env-provider"
+)
+
+func TestOcep(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ocep Suite")
+}
+
+var _ = BeforeEach(func() {
+	for _, env := range os.Environ() {
+		key := strings.SplitN(env, "=", 2)[0]
+		if strings.HasPrefix(key, "OCI_") {
+			_ = os.Unsetenv(key)
+		}
+	}
+	home, _ := os.MkdirTemp("", "ocep")
+	DeferCleanup(os.RemoveAll, home)
+	DeferCleanup(os.Setenv, "HOME", os.Getenv("HOME"))
+	_ = os.Setenv("HOME", home)
+})
+
+var _ = Describe("ocep", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	Context("api key environment", func() {
+		var keyFile string
+
+		BeforeEach(func() {
+			pk, _ := rsa.GenerateKey(rand.Reader, 2048)
+			f, _ := os.CreateTemp("", "ocep")
+			DeferCleanup(os.Remove, f.Name())
+			_, _ = f.Write(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(pk)}))
+			_ = f.Close()
+			keyFile = f.Name()
+			fingerprint, _ := ocep.PublicKeyFingerprint(&pk.PublicKey)
+
+			_ = os.Setenv(ocep.EnvTenancy, "ocid1.tenancy.oc1..aaaaaaaatesttenancy")
+			_ = os.Setenv(ocep.EnvUser, "ocid1.user.oc1..aaaaaaaatestuser")
+			_ = os.Setenv(ocep.EnvFingerprint, fingerprint)
+			_ = os.Setenv(ocep.EnvRegion, "us-ashburn-1")
+			_ = os.Setenv(ocep.EnvKeyFile, keyFile)
+			_ = os.Setenv(ocep.EnvAuth, string(ocep.ApiKeyType))
+		})
+
+		It("reports a valid configuration", func() {
+			Expect(run(nil, stdout, stderr)).To(Equal(0))
+			Expect(stdout.String()).To(ContainSubstring("ocid1.tenancy.oc1..aaaaaaaatesttenancy"))
+			Expect(stdout.String()).To(ContainSubstring("environment variable " + ocep.EnvTenancy))
+			Expect(stdout.String()).To(ContainSubstring(keyFile))
+			Expect(stdout.String()).To(ContainSubstring("configuration is valid"))
+		})
+
+		It("flags a stale fingerprint", func() {
+			_ = os.Setenv(ocep.EnvFingerprint, "test-fingerprint")
+
+			Expect(run(nil, stdout, stderr)).To(Equal(1))
+			Expect(stdout.String()).To(ContainSubstring("does not match the private key fingerprint"))
+		})
+	})
+
+	It("flags missing environment variables", func() {
+		_ = os.Setenv(ocep.EnvTenancy, "ocid1.tenancy.oc1..aaaaaaaatesttenancy")
+
+		Expect(run(nil, stdout, stderr)).To(Equal(1))
+		Expect(stdout.String()).To(ContainSubstring(ocep.EnvUser + " not set"))
+		Expect(stdout.String()).To(ContainSubstring(ocep.EnvKeyContent + ", " + ocep.EnvKeyFile + " not set"))
+		Expect(stdout.String()).To(ContainSubstring("configuration is not valid"))
+	})
+
+	It("does not resolve principal authentication", func() {
+		_ = os.Setenv(ocep.EnvAuth, string(ocep.InstancePrincipalType))
+
+		Expect(run(nil, stdout, stderr)).To(Equal(3))
+		Expect(stdout.String()).To(MatchRegexp(`profile:\s+DEFAULT\s+\(default\)`))
+		Expect(stdout.String()).To(MatchRegexp(`tenancy\s+\(fetched at runtime\)\s+instance_principal authentication from environment variable %s`, ocep.EnvAuth))
+		Expect(stdout.String()).To(ContainSubstring("cannot be checked offline"))
+		Expect(stdout.String()).ToNot(ContainSubstring("configuration is valid"))
+	})
+
+	It("sets the profile from the flag", func() {
+		Expect(run([]string{"-profile", "other"}, stdout, stderr)).To(Equal(1))
+		Expect(os.Getenv(ocep.EnvProfile)).To(BeEmpty())
+		Expect(stdout.String()).To(MatchRegexp(`profile:\s+other\s+\(flag -profile\)`))
+	})
+
+	It("reports the config file and profile", func() {
+		configFile, _ := os.CreateTemp("", "ocep")
+		DeferCleanup(os.Remove, configFile.Name())
+		_, _ = configFile.WriteString("[OCI_CLI_SETTINGS]\ndefault_profile = dev\n\n[prod]\nregion = us-ashburn-1\n")
+		_ = configFile.Close()
+
+		Expect(run([]string{"-config-file", configFile.Name()}, stdout, stderr)).To(Equal(1))
+		Expect(os.Getenv(ocep.EnvConfigFile)).To(BeEmpty())
+		Expect(stdout.String()).To(MatchRegexp(`config file:\s+%s\s+\(flag -config-file\)`, configFile.Name()))
+		Expect(stdout.String()).To(MatchRegexp(`profile:\s+dev\s+\(default_profile in config file %s\)`, configFile.Name()))
+		Expect(stdout.String()).To(ContainSubstring("could not load profile dev"))
+	})
+})
@@ -0,0 +1,181 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"crypto/rsa"
+	"errors"
+	"sync"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// CoherentConfigProvider takes a list of providers and returns a [common.ConfigurationProvider]
+// that answers every method from the first provider that is valid according to
+// [common.IsConfigurationProviderValid].
+//
+// Unlike [ComposingConfigProvider], values from different providers are never mixed, so a partially
+// set environment cannot be combined with the credentials of a config file. The provider is selected
+// on first use and kept from then on.
+func CoherentConfigProvider(providers ...common.ConfigurationProvider) common.ConfigurationProvider {
+	return &coherentProvider{providers: providers}
+}
+
+// CoherentConfigProviderWithRegion is like [CoherentConfigProvider], except the region is taken from
+// regionProvider whenever it supplies one. This allows e.g. OCI_CLI_REGION to override the region of
+// the selected provider.
+func CoherentConfigProviderWithRegion(regionProvider common.ConfigurationProvider, providers ...common.ConfigurationProvider) common.ConfigurationProvider {
+	return &coherentProvider{providers: providers, regionProvider: regionProvider}
+}
+
+type coherentProvider struct {
+	providers      []common.ConfigurationProvider
+	regionProvider common.ConfigurationProvider
+
+	mu       sync.Mutex
+	selected common.ConfigurationProvider
+	index    int
+	skipped  []SkippedProvider
+}
+
+func (c *coherentProvider) selectProvider() (common.ConfigurationProvider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.selected != nil {
+		return c.selected, nil
+	}
+
+	c.skipped = nil
+	errs := []error{ErrNoValidProvider}
+	for i, provider := range c.providers {
+		ok, err := common.IsConfigurationProviderValid(provider)
+		if ok {
+			c.selected, c.index = provider, i
+			return provider, nil
+		}
+		c.skipped = append(c.skipped, SkippedProvider{Index: i, Provider: provider, Source: describeSource(provider, FieldKeyID), Err: err})
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (c *coherentProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
+	provider, err := c.selectProvider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.PrivateRSAKey()
+}
+
+func (c *coherentProvider) KeyID() (string, error) {
+	provider, err := c.selectProvider()
+	if err != nil {
+		return "", err
+	}
+	return provider.KeyID()
+}
+
+func (c *coherentProvider) TenancyOCID() (string, error) {
+	provider, err := c.selectProvider()
+	if err != nil {
+		return "", err
+	}
+	return provider.TenancyOCID()
+}
+
+func (c *coherentProvider) UserOCID() (string, error) {
+	provider, err := c.selectProvider()
+	if err != nil {
+		return "", err
+	}
+	return provider.UserOCID()
+}
+
+func (c *coherentProvider) KeyFingerprint() (string, error) {
+	provider, err := c.selectProvider()
+	if err != nil {
+		return "", err
+	}
+	return provider.KeyFingerprint()
+}
+
+func (c *coherentProvider) Region() (string, error) {
+	if c.regionProvider != nil {
+		if region, err := c.regionProvider.Region(); err == nil {
+			return region, nil
+		}
+	}
+
+	provider, err := c.selectProvider()
+	if err != nil {
+		return "", err
+	}
+	return provider.Region()
+}
+
+func (c *coherentProvider) AuthType() (common.AuthConfig, error) {
+	provider, err := c.selectProvider()
+	if err != nil {
+		return common.AuthConfig{AuthType: common.UnknownAuthenticationType}, err
+	}
+	return provider.AuthType()
+}
+
+// Explain returns the selected provider as the source of every [Field], along with the reason
+// each preceding provider was not valid
+func (c *coherentProvider) Explain() Explanation {
+	explanation := make(Explanation, 0, len(Fields))
+	for _, field := range Fields {
+		explanation = append(explanation, c.explainField(field))
+	}
+	return explanation
+}
+
+func (c *coherentProvider) explainField(field Field) FieldExplanation {
+	selected, _ := c.selectProvider()
+
+	c.mu.Lock()
+	index, skipped := c.index, c.skipped
+	c.mu.Unlock()
+
+	fe := FieldExplanation{Field: field, Index: -1}
+	if field == FieldRegion && c.regionProvider != nil {
+		value, err := c.regionProvider.Region()
+		if err == nil {
+			fe.Value, fe.Provider, fe.Source = value, c.regionProvider, describeSource(c.regionProvider, field)
+			return fe
+		}
+		fe.Skipped = append(fe.Skipped, SkippedProvider{Index: -1, Provider: c.regionProvider, Source: describeSource(c.regionProvider, field), Err: err})
+	}
+	fe.Skipped = append(fe.Skipped, skipped...)
+
+	if selected != nil {
+		value, err := resolveField(selected, field)
+		if err == nil {
+			fe.Value, fe.Provider, fe.Index, fe.Source = value, selected, index, describeSource(selected, field)
+		} else {
+			fe.Skipped = append(fe.Skipped, SkippedProvider{Index: index, Provider: selected, Source: describeSource(selected, field), Err: err})
+		}
+	}
+	return fe
+}
+
+// DescribeSource describes the source of the provider that supplies the field
+func (c *coherentProvider) DescribeSource(field Field) string {
+	return c.explainField(field).Source
+}
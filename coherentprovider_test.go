@@ -0,0 +1,135 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"crypto/rsa"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+var _ = Describe("CoherentConfigProvider", func() {
+	var (
+		partialProvider common.ConfigurationProvider
+		fullProvider    common.ConfigurationProvider
+	)
+
+	BeforeEach(func() {
+		privateKeyPath := createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+
+		partialProvider = OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{
+			EnvTenancy: "ocid1.tenancy.oc1..aaaaaaaapartial",
+			EnvRegion:  "us-phoenix-1",
+		}))
+		fullProvider = OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{
+			EnvTenancy:     testTenancy,
+			EnvUser:        testUser,
+			EnvFingerprint: testFingerprint,
+			EnvRegion:      testRegion,
+			EnvKeyFile:     privateKeyPath,
+			EnvAuth:        string(ApiKeyType),
+		}))
+	})
+
+	It("answers every method from the first valid provider", func() {
+		conf := CoherentConfigProvider(partialProvider, fullProvider)
+		valid, err := common.IsConfigurationProviderValid(conf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeTrue())
+
+		Expect(conf.TenancyOCID()).To(Equal(testTenancy))
+		Expect(conf.Region()).To(Equal(testRegion))
+		Expect(conf.KeyID()).To(Equal(testTenancy + "/" + testUser + "/" + testFingerprint))
+
+		at, err := conf.AuthType()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(at.AuthType).To(Equal(common.UserPrincipal))
+	})
+
+	It("returns an error when no provider is valid", func() {
+		conf := CoherentConfigProvider(partialProvider)
+		_, err := conf.TenancyOCID()
+		Expect(err).To(MatchError(ErrNoValidProvider))
+		Expect(err).To(MatchError(ContainSubstring(EnvUser)))
+
+		at, err := conf.AuthType()
+		Expect(err).To(MatchError(ErrNoValidProvider))
+		Expect(at.AuthType).To(Equal(common.UnknownAuthenticationType))
+	})
+
+	When("the region is overridden", func() {
+		It("takes the region from the region provider", func() {
+			conf := CoherentConfigProviderWithRegion(partialProvider, partialProvider, fullProvider)
+			Expect(conf.Region()).To(Equal("us-phoenix-1"))
+			Expect(conf.TenancyOCID()).To(Equal(testTenancy))
+		})
+
+		It("falls back to the region of the selected provider", func() {
+			conf := CoherentConfigProviderWithRegion(OciCliEnvironmentConfigurationProvider(WithEnvMap(nil)), fullProvider)
+			Expect(conf.Region()).To(Equal(testRegion))
+		})
+	})
+
+	It("explains the selection", func() {
+		conf := CoherentConfigProviderWithRegion(partialProvider, partialProvider, fullProvider)
+		explanation := Explain(conf)
+
+		tenancy, _ := explanation.Field(FieldTenancy)
+		Expect(tenancy.Value).To(Equal(testTenancy))
+		Expect(tenancy.Index).To(Equal(1))
+		Expect(tenancy.Provider).To(BeIdenticalTo(fullProvider))
+		Expect(tenancy.Skipped).To(HaveLen(1))
+		Expect(tenancy.Skipped[0].Err).To(MatchError(ContainSubstring(EnvUser)))
+
+		region, _ := explanation.Field(FieldRegion)
+		Expect(region.Value).To(Equal("us-phoenix-1"))
+		Expect(region.Index).To(Equal(-1))
+		Expect(region.Provider).To(BeIdenticalTo(partialProvider))
+	})
+
+	It("describes a field without resolving the others", func() {
+		counting := &keyCountingProvider{ConfigurationProvider: fullProvider}
+		conf := CoherentConfigProvider(partialProvider, counting)
+		Expect(common.IsConfigurationProviderValid(conf)).To(BeTrue())
+		calls := counting.keyCalls
+
+		describer := conf.(SourceDescriber)
+		Expect(describer.DescribeSource(FieldTenancy)).To(Equal("environment variable " + EnvTenancy))
+		Expect(describer.DescribeSource(FieldRegion)).To(Equal("environment variable " + EnvRegion))
+		Expect(counting.keyCalls).To(Equal(calls))
+	})
+})
+
+// keyCountingProvider counts the calls of PrivateRSAKey
+type keyCountingProvider struct {
+	common.ConfigurationProvider
+	keyCalls int
+}
+
+func (p *keyCountingProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
+	p.keyCalls++
+	return p.ConfigurationProvider.PrivateRSAKey()
+}
+
+func (p *keyCountingProvider) DescribeSource(field Field) string {
+	return p.ConfigurationProvider.(SourceDescriber).DescribeSource(field)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package ocep
 
 import (
+	"time"
+
 	"github.com/oracle/oci-go-sdk/v65/common"
 )
 
@@ -44,3 +46,22 @@ func (c composingProvider) AuthType() (common.AuthConfig, error) {
 	}
 	return common.AuthConfig{AuthType: common.UnknownAuthenticationType}, ErrNoAuthType
 }
+
+// Explain returns which of the composed providers supplies each [Field]
+func (c composingProvider) Explain() Explanation {
+	return explainProviders(c.providers)
+}
+
+// DescribeSource describes the source of the provider that supplies the field
+func (c composingProvider) DescribeSource(field Field) string {
+	return explainField(c.providers, field).Source
+}
+
+// SecurityTokenExpiry returns the expiry of the security token of the provider that supplies the KeyID
+func (c composingProvider) SecurityTokenExpiry() (time.Time, error) {
+	fe := explainField(c.providers, FieldKeyID)
+	if !fe.Found() {
+		return time.Time{}, ErrNoKeyId
+	}
+	return securityTokenExpiry(fe.Provider)
+}
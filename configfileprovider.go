@@ -0,0 +1,306 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/ontariosystems/oci-cli-env-provider/ocid"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"gopkg.in/ini.v1"
+)
+
+// Keys of an oci cli config file profile
+const (
+	configKeyUser                = "user"
+	configKeyFingerprint         = "fingerprint"
+	configKeyKeyFile             = "key_file"
+	configKeyPassphrase          = "pass_phrase"
+	configKeyTenancy             = "tenancy"
+	configKeyRegion              = "region"
+	configKeySecurityTokenFile   = "security_token_file"
+	configKeyDelegationTokenFile = "delegation_token_file"
+)
+
+// configFieldKeys are the profile keys of the fields that are read from a single key
+var configFieldKeys = map[Field]string{
+	FieldTenancy:     configKeyTenancy,
+	FieldUser:        configKeyUser,
+	FieldFingerprint: configKeyFingerprint,
+	FieldRegion:      configKeyRegion,
+	FieldPrivateKey:  configKeyKeyFile,
+}
+
+// ConfigFileConfigurationProvider returns a [common.ConfigurationProvider] that gets values from a profile of
+// the oci cli config file, with the inheritance of [LoadConfigProfile]. The DEFAULT profile is used when
+// profileName is empty.
+//
+// Profiles created by `oci session authenticate` have a security_token_file instead of a user, and
+// authenticate with the security token the same as OCI_CLI_AUTH=security_token does.
+//
+// The options that do not select the environment, such as [WithPassphraseSource] and
+// [WithSecurityTokenRefresh], apply to the profile.
+func ConfigFileConfigurationProvider(configFilePath, profileName string, opts ...Option) common.ConfigurationProvider {
+	return newConfigFileProvider(newOptions(opts...), configFilePath, profileName)
+}
+
+func newConfigFileProvider(o *options, configFilePath, profileName string) *configFileProvider {
+	return &configFileProvider{options: o, filePath: configFilePath, profileName: profileName}
+}
+
+type configFileProvider struct {
+	*options
+	filePath    string
+	profileName string
+
+	configFile internal.CachedFile
+	tokenFile  securityTokenFile
+	keyFile    privateKeyFile
+
+	mu        sync.Mutex
+	contentID [sha256.Size]byte
+	cached    *ConfigProfile
+}
+
+// profile loads the profile, which is only parsed again when the content of the config file changes
+func (p *configFileProvider) profile() (*ConfigProfile, error) {
+	content, err := p.configFile.Read(internal.ExpandPath(p.filePath))
+	if err != nil {
+		return nil, err
+	}
+	id := sha256.Sum256(content)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cached != nil && p.contentID == id {
+		return p.cached, nil
+	}
+
+	cliConf, err := ini.Load(content)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := loadConfigProfile(cliConf, p.filePath, p.profileName)
+	if err != nil {
+		return nil, err
+	}
+	p.contentID, p.cached = id, profile
+	return profile, nil
+}
+
+// get returns the value of key, or a [ProfileKeyError] if it is not set
+func (p *configFileProvider) get(key string) (string, *ConfigProfile, error) {
+	profile, err := p.profile()
+	if err != nil {
+		return "", nil, err
+	}
+	value, ok := profile.Get(key)
+	if !ok || value == "" {
+		return "", profile, p.keyError(profile, key, nil)
+	}
+	return value, profile, nil
+}
+
+func (p *configFileProvider) keyError(profile *ConfigProfile, key string, err error) error {
+	return &ProfileKeyError{FilePath: p.filePath, Profile: profile.Name, Key: key, Err: err}
+}
+
+func (p *configFileProvider) getOCID(key, resourceType string) (string, error) {
+	value, profile, err := p.get(key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := ocid.ParseType(value, resourceType); err != nil {
+		return "", p.keyError(profile, key, err)
+	}
+	return value, nil
+}
+
+func (p *configFileProvider) TenancyOCID() (string, error) {
+	return p.getOCID(configKeyTenancy, tenancyResourceType)
+}
+
+// UserOCID returns "" for profiles with a security_token_file instead of a user, the same as the oci-go-sdk
+func (p *configFileProvider) UserOCID() (string, error) {
+	at, err := p.AuthType()
+	if err != nil || at.AuthType != SecurityTokenType {
+		return p.getOCID(configKeyUser, userResourceType)
+	}
+	return "", nil
+}
+
+func (p *configFileProvider) KeyFingerprint() (string, error) {
+	profile, err := p.profile()
+	if err != nil {
+		return "", err
+	}
+	value, _ := profile.Get(configKeyFingerprint)
+	return p.keyFingerprint(value, value != "", p.keyError(profile, configKeyFingerprint, nil), p.PrivateRSAKey)
+}
+
+func (p *configFileProvider) Region() (string, error) {
+	value, profile, err := p.get(configKeyRegion)
+	if err != nil {
+		return "", err
+	}
+	region, err := p.resolveRegion("", value)
+	if err != nil {
+		return "", p.keyError(profile, configKeyRegion, err)
+	}
+	return region, nil
+}
+
+func (p *configFileProvider) KeyID() (keyID string, err error) {
+	tenancy, err := p.TenancyOCID()
+	if err != nil {
+		return
+	}
+
+	fingerprint, err := p.KeyFingerprint()
+	if err != nil {
+		return
+	}
+
+	at, err := p.AuthType()
+	if err != nil {
+		return
+	}
+	switch at.AuthType {
+	case common.UserPrincipal:
+		var user string
+		if user, err = p.UserOCID(); err != nil {
+			return
+		}
+		keyID = fmt.Sprintf("%s/%s/%s", tenancy, user, fingerprint)
+		return
+	case SecurityTokenType:
+		var token string
+		if token, err = p.securityToken(); err != nil {
+			return
+		}
+		keyID = fmt.Sprintf("ST$%s", token)
+		return
+	}
+
+	err = ErrNoKeyId
+	return
+}
+
+// passphrase reads the passphrase from the [PassphraseSource] option, or else the pass_phrase of the profile
+// when the source gives back an empty passphrase
+func (p *configFileProvider) passphrase() (string, error) {
+	if p.passphraseSource != nil {
+		if passphrase, err := p.passphraseSource(); err != nil || passphrase != "" {
+			return passphrase, err
+		}
+	}
+
+	profile, err := p.profile()
+	if err != nil {
+		return "", err
+	}
+	value, _ := profile.Get(configKeyPassphrase)
+	return value, nil
+}
+
+func (p *configFileProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
+	keyPath, _, err := p.get(configKeyKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	passphrase, err := p.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	return p.keyFile.read(keyPath, passphrase, p.filePermissions)
+}
+
+// AuthType is [common.UserPrincipal] for profiles with a user, or [SecurityTokenType] for profiles
+// with a security_token_file
+func (p *configFileProvider) AuthType() (common.AuthConfig, error) {
+	profile, err := p.profile()
+	if err != nil {
+		return common.AuthConfig{AuthType: common.UnknownAuthenticationType}, err
+	}
+
+	if value, _ := profile.Get(configKeyUser); value != "" {
+		return common.AuthConfig{AuthType: common.UserPrincipal}, nil
+	}
+	if value, _ := profile.Get(configKeySecurityTokenFile); value != "" {
+		return common.AuthConfig{AuthType: SecurityTokenType}, nil
+	}
+	return common.AuthConfig{AuthType: common.UnknownAuthenticationType}, p.keyError(profile, configKeyUser, nil)
+}
+
+// DelegationToken returns the token in the delegation_token_file of the profile
+func (p *configFileProvider) DelegationToken() (string, error) {
+	tokenPath, _, err := p.get(configKeyDelegationTokenFile)
+	if err != nil {
+		return "", err
+	}
+	return readDelegationToken(tokenPath)
+}
+
+// SecurityTokenExpiry returns the expiry of the token in the security_token_file of the profile
+func (p *configFileProvider) SecurityTokenExpiry() (time.Time, error) {
+	tokenPath, _, err := p.get(configKeySecurityTokenFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return p.tokenFile.expiry(tokenPath, p.filePermissions)
+}
+
+func (p *configFileProvider) securityToken() (string, error) {
+	tokenPath, _, err := p.get(configKeySecurityTokenFile)
+	if err != nil {
+		return "", err
+	}
+	return p.tokenFile.token(tokenPath, p.options)
+}
+
+// DescribeSource names the config file and the profile the field is read from
+func (p *configFileProvider) DescribeSource(field Field) string {
+	profileName := p.profileName
+	if profileName == "" {
+		profileName = ini.DefaultSection
+	}
+
+	key := configFieldKeys[field]
+	profile, err := p.profile()
+	if err != nil || key == "" {
+		return fmt.Sprintf("config file %s [%s]", p.filePath, profileName)
+	}
+
+	value, ok := profile.Get(key)
+	if ok {
+		profileName = profile.Source(key)
+	}
+	switch {
+	case field == FieldFingerprint && !ok && p.deriveFingerprint:
+		return "derived from the private key in " + p.DescribeSource(FieldPrivateKey)
+	case field == FieldPrivateKey && ok:
+		return fmt.Sprintf("file %s from config file %s [%s]", internal.ExpandPath(value), p.filePath, profileName)
+	}
+	return fmt.Sprintf("config file %s [%s]", p.filePath, profileName)
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+	"github.com/ontariosystems/oci-cli-env-provider/ocid"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+var _ = Describe("ConfigFileConfigurationProvider", func() {
+	var (
+		privateKeyPath string
+		tokenPath      string
+		configFilePath string
+	)
+
+	writeConfig := func(format string, args ...any) {
+		Expect(os.WriteFile(configFilePath, []byte(fmt.Sprintf(format, args...)), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		privateKeyPath = createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+		tokenPath = createTempFile([]byte(testJWT(time.Now().Add(time.Hour))))
+		DeferCleanup(os.Remove, tokenPath)
+		configFilePath = createTempFile(nil)
+		DeferCleanup(os.Remove, configFilePath)
+
+		writeConfig(`[DEFAULT]
+tenancy = %s
+region = iad
+
+[api]
+user = %s
+fingerprint = %s
+key_file = %s
+
+[session]
+fingerprint = %s
+key_file = %s
+security_token_file = %s
+
+[session-phx]
+parent_profile = session
+region = us-phoenix-1
+`, testTenancy, testUser, testFingerprint, privateKeyPath, testFingerprint, privateKeyPath, tokenPath)
+	})
+
+	Context("api key profile", func() {
+		var conf common.ConfigurationProvider
+
+		BeforeEach(func() {
+			conf = ConfigFileConfigurationProvider(configFilePath, "api")
+		})
+
+		It("has valid configuration", func() {
+			valid, err := common.IsConfigurationProviderValid(conf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+
+			Expect(conf.KeyID()).To(Equal(testTenancy + "/" + testUser + "/" + testFingerprint))
+			Expect(conf.Region()).To(Equal("us-ashburn-1"))
+
+			at, err := conf.AuthType()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(at.AuthType).To(Equal(common.UserPrincipal))
+		})
+
+		It("describes where each value comes from", func() {
+			describer := conf.(SourceDescriber)
+			Expect(describer.DescribeSource(FieldUser)).To(Equal("config file " + configFilePath + " [api]"))
+			Expect(describer.DescribeSource(FieldTenancy)).To(Equal("config file " + configFilePath + " [DEFAULT]"))
+			Expect(describer.DescribeSource(FieldPrivateKey)).To(Equal("file " + privateKeyPath + " from config file " + configFilePath + " [api]"))
+		})
+
+		It("reads the config file again when it changes", func() {
+			Expect(conf.UserOCID()).To(Equal(testUser))
+
+			writeConfig("[api]\nuser = ocid1.user.oc1..aaaaaaaachanged\n")
+			Expect(conf.UserOCID()).To(Equal("ocid1.user.oc1..aaaaaaaachanged"))
+		})
+
+		It("picks up a changed inherited value", func() {
+			Expect(conf.TenancyOCID()).To(Equal(testTenancy))
+
+			writeConfig("[DEFAULT]\ntenancy = %s\n[api]\nuser = %s\n", testAltTenancy, testUser)
+			Expect(conf.TenancyOCID()).To(Equal(testAltTenancy))
+			Expect(conf.UserOCID()).To(Equal(testUser))
+		})
+
+		It("does not keep a profile that was removed", func() {
+			Expect(conf.UserOCID()).To(Equal(testUser))
+
+			writeConfig("[DEFAULT]\ntenancy = %s\n", testTenancy)
+			_, err := conf.UserOCID()
+			Expect(err).To(MatchError(ErrProfileNotFound))
+		})
+	})
+
+	Context("security token profile", func() {
+		var conf common.ConfigurationProvider
+
+		BeforeEach(func() {
+			conf = ConfigFileConfigurationProvider(configFilePath, "session-phx")
+		})
+
+		It("has valid configuration", func() {
+			valid, err := common.IsConfigurationProviderValid(conf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+
+			Expect(conf.UserOCID()).To(BeEmpty())
+			Expect(conf.Region()).To(Equal("us-phoenix-1"))
+			token, _ := os.ReadFile(tokenPath)
+			Expect(conf.KeyID()).To(Equal("ST$" + string(token)))
+
+			at, err := conf.AuthType()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(at.AuthType).To(Equal(SecurityTokenType))
+		})
+
+		It("reports the token expiry", func() {
+			expiry, err := conf.(SecurityTokenExpirer).SecurityTokenExpiry()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expiry).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+		})
+
+		It("rejects an expired token", func() {
+			Expect(os.WriteFile(tokenPath, []byte(testJWT(time.Now().Add(-time.Minute))), 0o600)).To(Succeed())
+			_, err := conf.KeyID()
+			Expect(err).To(MatchError(ErrSecurityTokenExpired))
+		})
+
+		It("refreshes the token", func() {
+			var refreshed string
+			conf = ConfigFileConfigurationProvider(configFilePath, "session", WithSecurityTokenRefresh(2*time.Hour, func(tokenFile string, _ time.Time) error {
+				refreshed = tokenFile
+				return os.WriteFile(tokenFile, []byte(testJWT(time.Now().Add(3*time.Hour))), 0o600)
+			}))
+
+			keyID, err := conf.KeyID()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(refreshed).To(Equal(tokenPath))
+			token, _ := os.ReadFile(tokenPath)
+			Expect(keyID).To(Equal("ST$" + string(token)))
+		})
+	})
+
+	It("uses DEFAULT without a profile name", func() {
+		conf := ConfigFileConfigurationProvider(configFilePath, "")
+		Expect(conf.TenancyOCID()).To(Equal(testTenancy))
+
+		_, err := conf.UserOCID()
+		var keyErr *ProfileKeyError
+		Expect(errors.As(err, &keyErr)).To(BeTrue())
+		Expect(keyErr.Profile).To(Equal("DEFAULT"))
+		Expect(keyErr.Key).To(Equal("user"))
+		Expect(keyErr.FilePath).To(Equal(configFilePath))
+	})
+
+	It("rejects a user OCID of the wrong type", func() {
+		writeConfig("[DEFAULT]\nuser = %s\n", testTenancy)
+		_, err := ConfigFileConfigurationProvider(configFilePath, "").UserOCID()
+		var keyErr *ProfileKeyError
+		Expect(errors.As(err, &keyErr)).To(BeTrue())
+		Expect(keyErr.Key).To(Equal("user"))
+		Expect(err).To(MatchError(ocid.ErrWrongResourceType))
+	})
+
+	It("rejects an unknown region", func() {
+		writeConfig("[DEFAULT]\nregion = us-ashburm-1\n")
+		_, err := ConfigFileConfigurationProvider(configFilePath, "").Region()
+		var regionErr *UnknownRegionError
+		Expect(errors.As(err, &regionErr)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("key region in profile DEFAULT")))
+	})
+
+	It("reports a missing profile", func() {
+		_, err := ConfigFileConfigurationProvider(configFilePath, "missing").TenancyOCID()
+		Expect(err).To(MatchError(ErrProfileNotFound))
+	})
+
+	Context("encrypted key", func() {
+		BeforeEach(func() {
+			encryptedKeyPath := createTempFile(testEncryptedPrivateKeyConf)
+			DeferCleanup(os.Remove, encryptedKeyPath)
+			writeConfig("[DEFAULT]\nkey_file = %s\npass_phrase = %s\n", encryptedKeyPath, testPassphrase)
+		})
+
+		It("uses the pass_phrase of the profile", func() {
+			key, err := ConfigFileConfigurationProvider(configFilePath, "").PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.Equal(testPk)).To(BeTrue())
+		})
+
+		It("prefers the passphrase source", func() {
+			conf := ConfigFileConfigurationProvider(configFilePath, "", WithPassphraseSource(func() (string, error) {
+				return "wrong-passphrase", nil
+			}))
+			_, err := conf.PrivateRSAKey()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("derives the fingerprint from the private key", func() {
+		writeConfig("[DEFAULT]\nkey_file = %s\n", privateKeyPath)
+		conf := ConfigFileConfigurationProvider(configFilePath, "", WithDerivedFingerprint())
+
+		expected, _ := PublicKeyFingerprint(&testPk.PublicKey)
+		Expect(conf.KeyFingerprint()).To(Equal(expected))
+		Expect(conf.(SourceDescriber).DescribeSource(FieldFingerprint)).To(HavePrefix("derived from the private key in file " + privateKeyPath))
+	})
+
+	It("reads the delegation token file", func() {
+		delegationTokenPath := createTempFile([]byte("test-delegation-token\n"))
+		DeferCleanup(os.Remove, delegationTokenPath)
+		writeConfig("[DEFAULT]\ndelegation_token_file = %s\n", delegationTokenPath)
+
+		conf := ConfigFileConfigurationProvider(configFilePath, "")
+		Expect(conf.(DelegationTokenProvider).DelegationToken()).To(Equal("test-delegation-token"))
+	})
+})
@@ -22,15 +22,29 @@ The standard [oci-cli environment variables] we use
 [oci-cli environment variables]: https://docs.oracle.com/en-us/iaas/Content/API/SDKDocs/clienvironmentvariables.htm
 */
 const (
-	EnvAuth              = "OCI_CLI_AUTH"
-	EnvConfigFile        = "OCI_CLI_CONFIG_FILE"
-	EnvFingerprint       = "OCI_CLI_FINGERPRINT"
-	EnvKeyContent        = "OCI_CLI_KEY_CONTENT"
-	EnvKeyFile           = "OCI_CLI_KEY_FILE"
-	EnvPassphrase        = "OCI_CLI_PASSPHRASE"
-	EnvProfile           = "OCI_CLI_PROFILE"
-	EnvRegion            = "OCI_CLI_REGION"
-	EnvSecurityTokenFile = "OCI_CLI_SECURITY_TOKEN_FILE"
-	EnvTenancy           = "OCI_CLI_TENANCY"
-	EnvUser              = "OCI_CLI_USER"
+	EnvAuth                  = "OCI_CLI_AUTH"
+	EnvCertBundle            = "OCI_CLI_CERT_BUNDLE"
+	EnvConfigFile            = "OCI_CLI_CONFIG_FILE"
+	EnvConnectionTimeout     = "OCI_CLI_CONNECTION_TIMEOUT"
+	EnvDelegationTokenFile   = "OCI_CLI_DELEGATION_TOKEN_FILE"
+	EnvEndpoint              = "OCI_CLI_ENDPOINT"
+	EnvFingerprint           = "OCI_CLI_FINGERPRINT"
+	EnvKeyContent            = "OCI_CLI_KEY_CONTENT"
+	EnvKeyFile               = "OCI_CLI_KEY_FILE"
+	EnvMaxRetries            = "OCI_CLI_MAX_RETRIES"
+	EnvPassphrase            = "OCI_CLI_PASSPHRASE"
+	EnvPassphraseFile        = "OCI_CLI_PASSPHRASE_FILE" // not used by the oci cli, keeps the passphrase out of the environment
+	EnvProfile               = "OCI_CLI_PROFILE"
+	EnvRcFile                = "OCI_CLI_RC_FILE"
+	EnvReadTimeout           = "OCI_CLI_READ_TIMEOUT"
+	EnvRealmSpecificEndpoint = "OCI_CLI_REALM_SPECIFIC_ENDPOINT"
+	EnvRegion                = "OCI_CLI_REGION"
+	EnvSecurityTokenFile     = "OCI_CLI_SECURITY_TOKEN_FILE"
+	EnvTenancy               = "OCI_CLI_TENANCY"
+	EnvUser                  = "OCI_CLI_USER"
 )
+
+// EnvRegionMetadata describes a region unknown to the oci-go-sdk, see [adding regions]
+//
+// [adding regions]: https://docs.oracle.com/en-us/iaas/Content/API/Concepts/sdk_adding_new_region_endpoints.htm
+const EnvRegionMetadata = "OCI_REGION_METADATA"
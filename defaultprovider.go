@@ -17,38 +17,111 @@ limitations under the License.
 package ocep
 
 import (
-	"os"
-
-	"github.com/ontariosystems/oci-cli-env-provider/internal"
 	"github.com/oracle/oci-go-sdk/v65/common"
-	"gopkg.in/ini.v1"
 )
 
+// ProviderPosition is where [WithProvider] adds a provider to the sources of [NewDefaultConfigProvider]
+type ProviderPosition int
+
+const (
+	// BeforeEnvironment consults the provider before the oci cli environment variables
+	BeforeEnvironment ProviderPosition = iota
+	// BeforeConfigFile consults the provider after the environment variables and before the config file profile
+	BeforeConfigFile
+	// BeforeSDKDefault consults the provider after the config file profile and before [common.DefaultConfigProvider]
+	BeforeSDKDefault
+	// Last consults the provider after every other source
+	Last
+)
+
+type positionedProvider struct {
+	position ProviderPosition
+	provider common.ConfigurationProvider
+}
+
 // DefaultConfigProvider returns a [common.ConfigurationProvider] containing providers for oci cli
-// environment variables, as well as those returned by [common.DefaultConfigProvider]
+// environment variables, as well as those returned by [common.DefaultConfigProvider]. It is the same
+// as [NewDefaultConfigProvider] without options.
 func DefaultConfigProvider() common.ConfigurationProvider {
+	return NewDefaultConfigProvider()
+}
+
+// NewDefaultConfigProvider returns a [common.ConfigurationProvider] that consults the oci cli environment
+// variables, then the config file profile, and then [common.DefaultConfigProvider]. [WithProvider] adds
+// other providers among them, and [WithSDKDefault] leaves out the oci-go-sdk default.
+//
+// The config file is set by [WithConfigFile], or else OCI_CLI_CONFIG_FILE, or else ~/.oci/config.
+// The profile is set by [WithProfile], or else OCI_CLI_PROFILE, or else the default_profile in the
+// OCI_CLI_SETTINGS section of the oci cli rc file (OCI_CLI_RC_FILE or ~/.oci/oci_cli_rc), or else of the
+// config file, or else DEFAULT. A config file that was chosen but does not exist is an error.
+//
+// When OCI_CLI_AUTH is one of [InstancePrincipalType], [ResourcePrincipalType], [OkeWorkloadIdentityType]
+// or [InstanceOboUserType], the result only contains the matching provider from the oci-go-sdk auth
+// package, which is not initialized until it is first used. OCI_CLI_REGION overrides the region of the
+// principal, while the other environment variables and the config file profile are not consulted then.
+// Providers added by [WithProvider] are still consulted, before the principal for [BeforeEnvironment] and
+// after it otherwise.
+//
+// The config file profile is read with [ConfigFileConfigurationProvider]. The passphrase of its key is read
+// the same way as the environment's, from [WithPassphraseSource], OCI_CLI_PASSPHRASE_FILE or
+// OCI_CLI_PASSPHRASE, or else from the pass_phrase of the profile.
+//
+// When the rc file, the config file or the profile cannot be loaded, the oci-go-sdk default configuration is
+// left out, so the configuration is not silently read from another profile. The result implements [Resolver],
+// which reports the config file and profile that were chosen, how, and the error loading them.
+//
+// With [WithStrictEnv], a partially set environment is an error rather than a reason to consult the other
+// sources, so it cannot be silently completed by someone else's config file.
+//
+// Use [Explain] on the result to find out which source supplies each value.
+func NewDefaultConfigProvider(opts ...DefaultOption) common.ConfigurationProvider {
 	var providers []common.ConfigurationProvider
 
-	envProvider := OciCliEnvironmentConfigurationProvider()
-	providers = append(providers, envProvider)
+	o := newDefaultOptions(opts...)
+	envProvider := newOciCliEnvProvider(o)
+	resolution := resolveProfile(o)
 
-	configFilePath := os.Getenv(EnvConfigFile)
-	if configFilePath == "" {
-		configFilePath = internal.ExpandPath("~/.oci/config")
+	// the principal takes the place of the environment, the config file and the oci-go-sdk default
+	if provider := principalConfigProvider(envProvider, resolution.ConfigFilePath, resolution.ProfileName); provider != nil {
+		// composing would hide the error of the principal provider
+		if len(o.extraProviders) == 0 {
+			return resolvedProvider{provider, resolution}
+		}
+		providers = append(providers, o.providersAt(BeforeEnvironment)...)
+		providers = append(providers, provider)
+		providers = append(providers, o.providersAt(BeforeConfigFile)...)
+		providers = append(providers, o.providersAt(BeforeSDKDefault)...)
+		providers = append(providers, o.providersAt(Last)...)
+		return resolvedProvider{ComposingConfigProvider(providers...), resolution}
 	}
 
-	profileName := os.Getenv(EnvProfile)
-	if profileName == "" {
-		if cliConf, err := ini.Load(configFilePath); err == nil {
-			profileName = cliConf.Section("OCI_CLI_SETTINGS").Key("default_profile").String()
-		}
+	if o.strictEnv && envProvider.credentialsSet() {
+		return resolvedProvider{strictEnvProvider{envProvider}, resolution}
 	}
 
-	if profileName != "" {
-		p, _ := common.ConfigurationProviderFromFileWithProfile(configFilePath, profileName, envProvider.(*ociCliEnvProvider).Passphrase())
-		providers = append(providers, p)
+	providers = append(providers, o.providersAt(BeforeEnvironment)...)
+	providers = append(providers, envProvider)
+	providers = append(providers, o.providersAt(BeforeConfigFile)...)
+
+	fileOptions := *o
+	fileOptions.passphraseSource = envProvider.passphrase
+	providers = append(providers, newConfigFileProvider(&fileOptions, resolution.ConfigFilePath, resolution.ProfileName))
+
+	providers = append(providers, o.providersAt(BeforeSDKDefault)...)
+	// the oci-go-sdk default would silently use another profile than the one that could not be loaded
+	if resolution.Err == nil && !o.excludeSDKDefault {
+		providers = append(providers, describedProvider{common.DefaultConfigProvider(), "oci-go-sdk default configuration"})
 	}
+	providers = append(providers, o.providersAt(Last)...)
+	return resolvedProvider{ComposingConfigProvider(providers...), resolution}
+}
 
-	providers = append(providers, common.DefaultConfigProvider())
-	return ComposingConfigProvider(providers...)
+func (o *options) providersAt(position ProviderPosition) []common.ConfigurationProvider {
+	var providers []common.ConfigurationProvider
+	for _, p := range o.extraProviders {
+		if p.position == position {
+			providers = append(providers, p.provider)
+		}
+	}
+	return providers
 }
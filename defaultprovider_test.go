@@ -18,9 +18,14 @@ package ocep_test
 
 import (
 	"bytes"
+	"errors"
 	"html/template"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"strings"
+	"sync/atomic"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -159,6 +164,287 @@ var _ = Describe("DefaultConfigProvider", func() {
 	})
 })
 
+var _ = Describe("DefaultConfigProvider profile resolution", func() {
+	var rcFile string
+
+	BeforeEach(func() {
+		privateKeyPath := createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+		testCliConfigFileTmplData.TestKeyFile = privateKeyPath
+		testCliConfigFileTmplData.AltKeyFile = privateKeyPath
+
+		b := &bytes.Buffer{}
+		_ = template.Must(template.New("").Parse(testCliConfigFileTmpl)).Execute(b, testCliConfigFileTmplData)
+		configFile := createTempFile(b.Bytes())
+		DeferCleanup(os.Remove, configFile)
+		_ = os.Setenv(EnvConfigFile, configFile)
+
+		rcFile = createTempFile([]byte("[OCI_CLI_SETTINGS]\ndefault_profile = alt\n"))
+		DeferCleanup(os.Remove, rcFile)
+	})
+
+	It("uses the default profile of the config file", func() {
+		_ = os.Setenv(EnvRcFile, "/does/not/exist")
+		Expect(DefaultConfigProvider().TenancyOCID()).To(Equal(testTenancy))
+	})
+
+	It("prefers the default profile of the rc file", func() {
+		_ = os.Setenv(EnvRcFile, rcFile)
+		Expect(DefaultConfigProvider().TenancyOCID()).To(Equal(testAltTenancy))
+	})
+
+	It("prefers the profile environment variable", func() {
+		_ = os.Setenv(EnvRcFile, rcFile)
+		_ = os.Setenv(EnvProfile, "test")
+		Expect(DefaultConfigProvider().TenancyOCID()).To(Equal(testTenancy))
+	})
+
+	It("reads the rc file from the home directory by default", func() {
+		home := createTempDir()
+		DeferCleanup(os.RemoveAll, home)
+		DeferCleanup(os.Setenv, "HOME", os.Getenv("HOME"))
+		_ = os.Mkdir(path.Join(home, ".oci"), 0700)
+		rc, _ := os.ReadFile(rcFile)
+		_ = os.WriteFile(path.Join(home, ".oci", "oci_cli_rc"), rc, 0600)
+		_ = os.Setenv("HOME", home)
+
+		Expect(DefaultConfigProvider().TenancyOCID()).To(Equal(testAltTenancy))
+	})
+
+	It("reports how the config file and profile were chosen", func() {
+		_ = os.Setenv(EnvRcFile, rcFile)
+		resolution := DefaultConfigProvider().(Resolver).Resolution()
+		Expect(resolution.ConfigFilePath).To(Equal(os.Getenv(EnvConfigFile)))
+		Expect(resolution.ConfigFileSource).To(Equal("environment variable " + EnvConfigFile))
+		Expect(resolution.RcFilePath).To(Equal(rcFile))
+		Expect(resolution.RcFileSource).To(Equal("environment variable " + EnvRcFile))
+		Expect(resolution.ProfileName).To(Equal("alt"))
+		Expect(resolution.ProfileSource).To(Equal("default_profile in rc file " + rcFile))
+		Expect(resolution.Err).ToNot(HaveOccurred())
+	})
+
+	It("reports the default profile of the config file", func() {
+		_ = os.Setenv(EnvRcFile, "/does/not/exist")
+		resolution := DefaultConfigProvider().(Resolver).Resolution()
+		Expect(resolution.ProfileName).To(Equal("test"))
+		Expect(resolution.ProfileSource).To(Equal("default_profile in config file " + os.Getenv(EnvConfigFile)))
+		Expect(resolution.Err).ToNot(HaveOccurred())
+	})
+
+	It("uses the default paths", func() {
+		home := createTempDir()
+		DeferCleanup(os.RemoveAll, home)
+		DeferCleanup(os.Setenv, "HOME", os.Getenv("HOME"))
+		_ = os.Setenv("HOME", home)
+		_ = os.Unsetenv(EnvConfigFile)
+
+		resolution := DefaultConfigProvider().(Resolver).Resolution()
+		Expect(resolution.ConfigFilePath).To(Equal(path.Join(home, ".oci", "config")))
+		Expect(resolution.ConfigFileSource).To(Equal("default"))
+		Expect(resolution.RcFilePath).To(Equal(path.Join(home, ".oci", "oci_cli_rc")))
+		Expect(resolution.RcFileSource).To(Equal("default"))
+		Expect(resolution.ProfileName).To(Equal("DEFAULT"))
+		Expect(resolution.ProfileSource).To(Equal("default"))
+		Expect(resolution.Err).ToNot(HaveOccurred())
+	})
+
+	It("expands ~ in the paths of the rc and config files", func() {
+		home := createTempDir()
+		DeferCleanup(os.RemoveAll, home)
+		DeferCleanup(os.Setenv, "HOME", os.Getenv("HOME"))
+		_ = os.Setenv("HOME", home)
+		config, _ := os.ReadFile(os.Getenv(EnvConfigFile))
+		_ = os.WriteFile(path.Join(home, "probe_config"), config, 0600)
+		_ = os.WriteFile(path.Join(home, "probe_rc"), []byte("[OCI_CLI_SETTINGS]\ndefault_profile = alt\n"), 0600)
+		_ = os.Setenv(EnvConfigFile, "~/probe_config")
+		_ = os.Setenv(EnvRcFile, "~/probe_rc")
+
+		conf := DefaultConfigProvider()
+		resolution := conf.(Resolver).Resolution()
+		Expect(resolution.ConfigFilePath).To(Equal(path.Join(home, "probe_config")))
+		Expect(resolution.RcFilePath).To(Equal(path.Join(home, "probe_rc")))
+		Expect(resolution.ProfileName).To(Equal("alt"))
+		Expect(resolution.Err).ToNot(HaveOccurred())
+		Expect(conf.TenancyOCID()).To(Equal(testAltTenancy))
+	})
+
+	It("reads the DEFAULT profile of the chosen config file", func() {
+		configFile := createTempFile([]byte("[DEFAULT]\ntenancy = " + testAltTenancy + "\n"))
+		DeferCleanup(os.Remove, configFile)
+		_ = os.Setenv(EnvRcFile, "/does/not/exist")
+
+		conf := NewDefaultConfigProvider(WithConfigFile(configFile), WithSDKDefault(false))
+		Expect(conf.TenancyOCID()).To(Equal(testAltTenancy))
+		resolution := conf.(Resolver).Resolution()
+		Expect(resolution.ProfileName).To(Equal("DEFAULT"))
+		Expect(resolution.ProfileSource).To(Equal("default"))
+		Expect(resolution.Err).ToNot(HaveOccurred())
+	})
+
+	It("reports a chosen config file that does not exist", func() {
+		_ = os.Setenv(EnvRcFile, "/does/not/exist")
+
+		conf := NewDefaultConfigProvider(WithConfigFile("/does/not/exist/config"))
+		Expect(conf.(Resolver).Resolution().Err).To(MatchError(ContainSubstring("config file /does/not/exist/config from option does not exist")))
+		tenancy, _ := Explain(conf).Field(FieldTenancy)
+		Expect(tenancy.Skipped).To(HaveLen(2))
+	})
+
+	When("the profile does not exist", func() {
+		BeforeEach(func() {
+			_ = os.Setenv(EnvProfile, "tset")
+		})
+
+		It("reports the error", func() {
+			resolution := DefaultConfigProvider().(Resolver).Resolution()
+			Expect(resolution.ProfileName).To(Equal("tset"))
+			Expect(resolution.ProfileSource).To(Equal("environment variable " + EnvProfile))
+			Expect(resolution.Err).To(MatchError(ErrProfileNotFound))
+		})
+
+		It("does not fall through to the oci-go-sdk default configuration", func() {
+			conf := DefaultConfigProvider()
+			_, err := conf.TenancyOCID()
+			Expect(err).To(HaveOccurred())
+
+			tenancy, _ := Explain(conf).Field(FieldTenancy)
+			Expect(tenancy.Skipped).To(HaveLen(2))
+			Expect(tenancy.Skipped[1].Err).To(MatchError(ErrProfileNotFound))
+		})
+	})
+
+	It("reports an rc file that cannot be loaded", func() {
+		badRcFile := createTempFile([]byte("[OCI_CLI_SETTINGS\n"))
+		DeferCleanup(os.Remove, badRcFile)
+		_ = os.Setenv(EnvRcFile, badRcFile)
+
+		resolution := DefaultConfigProvider().(Resolver).Resolution()
+		Expect(resolution.Err).To(MatchError(ContainSubstring("could not load rc file " + badRcFile)))
+		Expect(resolution.ProfileName).To(Equal("test"))
+	})
+})
+
+var _ = Describe("DefaultConfigProvider with principal authentication", func() {
+	BeforeEach(func() {
+		_ = os.Setenv(EnvUser, testUser)
+		_ = os.Setenv(EnvTenancy, testTenancy)
+		_ = os.Setenv(EnvFingerprint, testFingerprint)
+		_ = os.Setenv(EnvRegion, testRegion)
+	})
+
+	DescribeTable("dispatches to the principal provider",
+		func(authType common.AuthenticationType, expectedErr string) {
+			_ = os.Setenv(EnvAuth, string(authType))
+			conf := DefaultConfigProvider()
+
+			_, err := conf.TenancyOCID()
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+
+			tenancy, _ := Explain(conf).Field(FieldTenancy)
+			Expect(tenancy.Found()).To(BeFalse())
+			Expect(tenancy.Skipped).To(HaveLen(1))
+			Expect(tenancy.Skipped[0].Source).To(ContainSubstring(string(authType)))
+
+			Expect(conf.Region()).To(Equal(testRegion))
+		},
+		Entry("resource principal", ResourcePrincipalType, "OCI_RESOURCE_PRINCIPAL_VERSION"),
+		Entry("oke workload identity", OkeWorkloadIdentityType, "OCI_RESOURCE_PRINCIPAL_VERSION"),
+		Entry("instance obo user", InstanceOboUserType, "no such file"),
+	)
+
+	It("does not initialize the principal provider until it is used", func() {
+		var requests atomic.Int32
+		metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if strings.HasSuffix(r.URL.Path, "/instance/region") {
+				_, _ = w.Write([]byte(testRegion))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		DeferCleanup(metadata.Close)
+		DeferCleanup(os.Unsetenv, "OCI_METADATA_BASE_URL")
+		_ = os.Setenv("OCI_METADATA_BASE_URL", metadata.URL+"/opc/v2")
+		_ = os.Setenv(EnvAuth, string(InstancePrincipalType))
+
+		conf := DefaultConfigProvider()
+		Expect(requests.Load()).To(BeZero())
+
+		_, err := conf.TenancyOCID()
+		Expect(err).To(HaveOccurred())
+		Expect(requests.Load()).ToNot(BeZero())
+	})
+
+	It("overrides the region with OCI_CLI_REGION", func() {
+		_ = os.Setenv(EnvAuth, string(ResourcePrincipalType))
+		_ = os.Setenv(EnvRegion, "phx")
+
+		conf := DefaultConfigProvider()
+		Expect(conf.Region()).To(Equal("us-phoenix-1"))
+		region, _ := Explain(conf).Field(FieldRegion)
+		Expect(region.Source).To(Equal("environment variable " + EnvRegion))
+
+		_, err := conf.TenancyOCID()
+		Expect(err).To(MatchError(ContainSubstring("OCI_RESOURCE_PRINCIPAL_VERSION")))
+	})
+
+	It("reports an unknown OCI_CLI_REGION", func() {
+		_ = os.Setenv(EnvAuth, string(InstancePrincipalType))
+		_ = os.Setenv(EnvRegion, "us-ashburm-1")
+
+		_, err := DefaultConfigProvider().Region()
+		var regionErr *UnknownRegionError
+		Expect(errors.As(err, &regionErr)).To(BeTrue())
+	})
+
+	When("the profile has no delegation token file", func() {
+		BeforeEach(func() {
+			configFile := createTempFile([]byte("[DEFAULT]\n[obo]\nregion = " + testRegion + "\n"))
+			DeferCleanup(os.Remove, configFile)
+			_ = os.Setenv(EnvConfigFile, configFile)
+			_ = os.Setenv(EnvProfile, "obo")
+			_ = os.Setenv(EnvAuth, string(InstanceOboUserType))
+		})
+
+		It("returns an error naming the profile", func() {
+			_, err := DefaultConfigProvider().KeyID()
+			Expect(err).To(MatchError(ContainSubstring("delegation_token_file in profile obo")))
+		})
+
+		It("prefers the delegation token file environment variable", func() {
+			_ = os.Setenv(EnvDelegationTokenFile, "/does/not/exist")
+			_, err := DefaultConfigProvider().KeyID()
+			Expect(err).To(MatchError(ContainSubstring("/does/not/exist")))
+		})
+	})
+
+	It("inherits the delegation token file from DEFAULT", func() {
+		configFile := createTempFile([]byte("[DEFAULT]\ndelegation_token_file = /does/not/exist/default\n[obo]\nregion = " + testRegion + "\n"))
+		DeferCleanup(os.Remove, configFile)
+		_ = os.Setenv(EnvConfigFile, configFile)
+		_ = os.Setenv(EnvProfile, "obo")
+		_ = os.Setenv(EnvAuth, string(InstanceOboUserType))
+
+		_, err := DefaultConfigProvider().KeyID()
+		Expect(err).To(MatchError(ContainSubstring("/does/not/exist/default")))
+	})
+})
+
+var _ = Describe("DelegationTokenConfigProvider", func() {
+	It("requires the delegation token file environment variable", func() {
+		_, err := DelegationTokenConfigProvider(WithEnvMap(nil)).TenancyOCID()
+		Expect(err).To(MatchError(&EnvError{EnvDelegationTokenFile}))
+	})
+
+	It("reads the delegation token from the environment", func() {
+		tokenPath := createTempFile([]byte("test-delegation-token\n"))
+		DeferCleanup(os.Remove, tokenPath)
+
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{EnvDelegationTokenFile: tokenPath}))
+		Expect(conf.(DelegationTokenProvider).DelegationToken()).To(Equal("test-delegation-token"))
+	})
+})
+
 func createTempDir() string {
 	tmp, _ := os.MkdirTemp("", "ociclienvprovider")
 	return tmp
@@ -194,7 +480,153 @@ security_token_file = {{ .AltSecurityTokenFile }}
 		TestTenancy:     testTenancy,
 		TestRegion:      testRegion,
 		AltFingerprint:  "alt-" + testFingerprint,
-		AltTenancy:      "alt-" + testTenancy,
-		AltRegion:       "alt-" + testRegion,
+		AltTenancy:      testAltTenancy,
+		AltRegion:       "us-phoenix-1",
 	}
 )
+
+var _ = Describe("NewDefaultConfigProvider", func() {
+	var (
+		configFile   string
+		fullProvider common.ConfigurationProvider
+	)
+
+	sources := func(conf common.ConfigurationProvider) []string {
+		tenancy, _ := Explain(conf).Field(FieldTenancy)
+		var result []string
+		for _, skipped := range tenancy.Skipped {
+			result = append(result, skipped.Source)
+		}
+		if tenancy.Found() {
+			result = append(result, tenancy.Source)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		privateKeyPath := createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+		testCliConfigFileTmplData.TestKeyFile = privateKeyPath
+		testCliConfigFileTmplData.AltKeyFile = privateKeyPath
+
+		b := &bytes.Buffer{}
+		_ = template.Must(template.New("").Parse(testCliConfigFileTmpl)).Execute(b, testCliConfigFileTmplData)
+		configFile = createTempFile(b.Bytes())
+		DeferCleanup(os.Remove, configFile)
+
+		fullProvider = OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{
+			EnvTenancy:     testTenancy,
+			EnvUser:        testUser,
+			EnvFingerprint: testFingerprint,
+			EnvRegion:      testRegion,
+			EnvKeyFile:     privateKeyPath,
+		}))
+	})
+
+	It("uses the config file and profile options", func() {
+		_ = os.Setenv(EnvConfigFile, "/does/not/exist")
+		_ = os.Setenv(EnvProfile, "test")
+
+		conf := NewDefaultConfigProvider(WithConfigFile(configFile), WithProfile("alt"))
+		Expect(conf.TenancyOCID()).To(Equal(testAltTenancy))
+
+		resolution := conf.(Resolver).Resolution()
+		Expect(resolution.ConfigFilePath).To(Equal(configFile))
+		Expect(resolution.ConfigFileSource).To(Equal("option"))
+		Expect(resolution.ProfileName).To(Equal("alt"))
+		Expect(resolution.ProfileSource).To(Equal("option"))
+	})
+
+	It("reads the environment from the env source", func() {
+		_ = os.Setenv(EnvTenancy, "ocid1.tenancy.oc1..aaaaaaaaprocessenv")
+
+		conf := NewDefaultConfigProvider(WithEnvMap(map[string]string{
+			EnvConfigFile: configFile,
+			EnvProfile:    "alt",
+		}))
+		Expect(conf.TenancyOCID()).To(Equal(testAltTenancy))
+		Expect(conf.(Resolver).Resolution().ProfileSource).To(Equal("environment variable " + EnvProfile))
+	})
+
+	It("leaves out the oci-go-sdk default", func() {
+		conf := NewDefaultConfigProvider(WithEnvMap(nil), WithConfigFile(configFile), WithSDKDefault(false))
+		Expect(sources(conf)).To(Equal([]string{
+			"environment variable " + EnvTenancy,
+			"config file " + configFile + " [test]",
+		}))
+	})
+
+	It("includes the oci-go-sdk default", func() {
+		conf := NewDefaultConfigProvider(WithEnvMap(nil), WithConfigFile(configFile), WithProfile("missing"), WithSDKDefault(true))
+		Expect(sources(conf)).ToNot(ContainElement("oci-go-sdk default configuration"))
+
+		conf = NewDefaultConfigProvider(WithEnvMap(nil), WithConfigFile(configFile), WithProfile("alt"), WithSDKDefault(true))
+		tenancy, _ := Explain(conf).Field(FieldTenancy)
+		Expect(tenancy.Index).To(Equal(1))
+		Expect(conf.(Explainer).Explain()).To(HaveLen(len(Fields)))
+	})
+
+	DescribeTable("adds providers at the chosen position",
+		func(position ProviderPosition, index int) {
+			conf := NewDefaultConfigProvider(
+				WithEnvMap(nil),
+				WithConfigFile(configFile),
+				WithProfile("missing"),
+				WithProvider(position, fullProvider),
+			)
+			tenancy, _ := Explain(conf).Field(FieldTenancy)
+			Expect(tenancy.Provider).To(BeIdenticalTo(fullProvider))
+			Expect(tenancy.Index).To(Equal(index))
+		},
+		Entry("before the environment", BeforeEnvironment, 0),
+		Entry("before the config file", BeforeConfigFile, 1),
+		Entry("before the oci-go-sdk default", BeforeSDKDefault, 2),
+		Entry("last", Last, 2),
+	)
+
+	It("keeps the order of providers at the same position", func() {
+		other := OciCliEnvironmentConfigurationProvider(WithEnvMap(nil))
+		conf := NewDefaultConfigProvider(
+			WithEnvMap(nil),
+			WithSDKDefault(false),
+			WithProvider(Last, other),
+			WithProvider(Last, fullProvider),
+		)
+		tenancy, _ := Explain(conf).Field(FieldTenancy)
+		Expect(tenancy.Index).To(Equal(3))
+		Expect(tenancy.Skipped[2].Provider).To(BeIdenticalTo(other))
+	})
+
+	DescribeTable("adds providers around the principal provider",
+		func(position ProviderPosition, index int) {
+			conf := NewDefaultConfigProvider(
+				WithEnvMap(map[string]string{EnvAuth: string(ResourcePrincipalType)}),
+				WithProvider(position, fullProvider),
+			)
+			Expect(conf.TenancyOCID()).To(Equal(testTenancy))
+			tenancy, _ := Explain(conf).Field(FieldTenancy)
+			Expect(tenancy.Provider).To(BeIdenticalTo(fullProvider))
+			Expect(tenancy.Index).To(Equal(index))
+		},
+		Entry("before the environment", BeforeEnvironment, 0),
+		Entry("last", Last, 1),
+	)
+
+	It("uses the passphrase source for the config file profile", func() {
+		encryptedKeyPath := createTempFile(testEncryptedPrivateKeyConf)
+		DeferCleanup(os.Remove, encryptedKeyPath)
+		encryptedConfigFile := createTempFile([]byte("[DEFAULT]\n[enc]\nkey_file = " + encryptedKeyPath + "\n"))
+		DeferCleanup(os.Remove, encryptedConfigFile)
+
+		conf := NewDefaultConfigProvider(
+			WithEnvMap(nil),
+			WithConfigFile(encryptedConfigFile),
+			WithProfile("enc"),
+			WithSDKDefault(false),
+			WithPassphraseSource(func() (string, error) { return testPassphrase, nil }),
+		)
+		key, err := conf.PrivateRSAKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.Equal(testPk)).To(BeTrue())
+	})
+})
@@ -17,13 +17,20 @@ limitations under the License.
 package ocep
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"strings"
+	"time"
 )
 
 var (
-	ErrNoKeyId    = errors.New("could not determine KeyID")
-	ErrNoAuthType = errors.New("could not determine AuthType")
+	ErrNoKeyId              = errors.New("could not determine KeyID")
+	ErrNoAuthType           = errors.New("could not determine AuthType")
+	ErrNoValidProvider      = errors.New("no valid configuration provider")
+	ErrSecurityTokenExpired = errors.New("security token expired")
+	ErrProfileNotFound      = errors.New("profile not found")
 )
 
 type EnvError struct {
@@ -33,3 +40,118 @@ type EnvError struct {
 func (e EnvError) Error() string {
 	return fmt.Sprintf("environment variable %s is not set", e.EnvVar)
 }
+
+// InitTimeoutError is returned when the initialization of a lazy provider did not finish in time
+type InitTimeoutError struct {
+	Timeout time.Duration
+	// Err is the error the initialization func returned after its context was cancelled, if any
+	Err error
+}
+
+func (e InitTimeoutError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("provider initialization did not finish within %s: %v", e.Timeout, e.Err)
+	}
+	return fmt.Sprintf("provider initialization did not finish within %s", e.Timeout)
+}
+
+func (e InitTimeoutError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+	return context.DeadlineExceeded
+}
+
+// InsecureKeyFileError is returned when a private key or security token file can be accessed by
+// other users and the [FilePermissionPolicy] is [RejectInsecureFiles]
+type InsecureKeyFileError struct {
+	Path   string
+	Mode   fs.FileMode
+	Reason string
+}
+
+func (e InsecureKeyFileError) Error() string {
+	return fmt.Sprintf("%s is insecure: %s", e.Path, e.Reason)
+}
+
+// FingerprintMismatchError is returned when the configured fingerprint does not belong to the private key
+type FingerprintMismatchError struct {
+	// Fingerprint is the configured fingerprint
+	Fingerprint string
+	// KeyFingerprint is the fingerprint of the private key
+	KeyFingerprint string
+}
+
+func (e FingerprintMismatchError) Error() string {
+	return fmt.Sprintf("fingerprint %s does not match the private key fingerprint %s", e.Fingerprint, e.KeyFingerprint)
+}
+
+// InvalidOCIDError is returned when an environment variable does not hold a valid OCID of the expected resource type
+type InvalidOCIDError struct {
+	EnvVar       string
+	ResourceType string
+	Err          error
+}
+
+func (e InvalidOCIDError) Error() string {
+	return fmt.Sprintf("environment variable %s is not a valid %s OCID: %v", e.EnvVar, e.ResourceType, e.Err)
+}
+
+func (e InvalidOCIDError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownRegionError is returned when a region is neither a known region identifier nor a known short code
+type UnknownRegionError struct {
+	// EnvVar is the environment variable the region is read from, if any
+	EnvVar string
+	Region string
+	// Err is the error reading the regions config file, if any
+	Err error
+}
+
+func (e UnknownRegionError) Error() string {
+	msg := fmt.Sprintf("unknown region %q", e.Region)
+	if e.EnvVar != "" {
+		msg = fmt.Sprintf("environment variable %s has %s", e.EnvVar, msg)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e UnknownRegionError) Unwrap() error {
+	return e.Err
+}
+
+// ProfileCycleError is returned when the parent_profile keys of the oci cli config file form a cycle
+type ProfileCycleError struct {
+	FilePath string
+	// Profiles lists the profiles of the cycle, starting and ending with the same profile
+	Profiles []string
+}
+
+func (e ProfileCycleError) Error() string {
+	return fmt.Sprintf("profile inheritance cycle in %s: %s", e.FilePath, strings.Join(e.Profiles, " -> "))
+}
+
+// ProfileKeyError is returned when a key of an oci cli config file profile is not set or not valid
+type ProfileKeyError struct {
+	FilePath string
+	Profile  string
+	Key      string
+	// Err is the reason the value is not valid, or nil when the key is not set
+	Err error
+}
+
+func (e ProfileKeyError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("key %s in profile %s of %s is not valid: %v", e.Key, e.Profile, e.FilePath, e.Err)
+	}
+	return fmt.Sprintf("key %s is not set in profile %s of %s", e.Key, e.Profile, e.FilePath)
+}
+
+func (e ProfileKeyError) Unwrap() error {
+	return e.Err
+}
@@ -19,6 +19,7 @@ package ocep_test
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/ontariosystems/oci-cli-env-provider"
 	"github.com/oracle/oci-go-sdk/v65/common"
@@ -82,3 +83,33 @@ func ExampleLazyConfigProvider() {
 	resp, _ := client.GetCompartment(context.TODO(), req)
 	fmt.Printf("CompartmentId: %s\n", *resp.Id)
 }
+
+// [WithWarmUp] starts initializing the instance principal credentials as soon as the provider is
+// created, and [Warm] lets a health check report ready only once they are available.
+func ExampleWarm() {
+	provider := ocep.LazyConfigProvider(auth.InstancePrincipalConfigurationProvider, ocep.WithWarmUp())
+
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if err := ocep.Warm(r.Context(), provider); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// [ConfigureClient] applies the endpoint, certificate, proxy, timeout and retry settings of the OCI CLI
+// environment to a service client, the same as the OCI CLI does.
+func ExampleConfigureClient() {
+	provider := ocep.DefaultConfigProvider()
+	client, _ := identity.NewIdentityClientWithConfigurationProvider(provider)
+	if err := ocep.ConfigureClient(&client.BaseClient); err != nil {
+		panic(err)
+	}
+
+	tenancyID, _ := provider.TenancyOCID()
+	req := identity.GetCompartmentRequest{
+		CompartmentId:   common.String(tenancyID),
+		RequestMetadata: metadata,
+	}
+	resp, _ := client.GetCompartment(context.TODO(), req)
+	fmt.Printf("CompartmentId: %s\n", *resp.Id)
+}
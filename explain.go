@@ -0,0 +1,188 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// Field identifies a value supplied by a [common.ConfigurationProvider]
+type Field string
+
+const (
+	FieldTenancy     Field = "tenancy"
+	FieldUser        Field = "user"
+	FieldFingerprint Field = "fingerprint"
+	FieldRegion      Field = "region"
+	FieldKeyID       Field = "key_id"
+	FieldPrivateKey  Field = "private_key"
+	FieldAuthType    Field = "auth_type"
+)
+
+// Fields is every [Field] in the order they are explained
+var Fields = []Field{FieldTenancy, FieldUser, FieldFingerprint, FieldRegion, FieldKeyID, FieldPrivateKey, FieldAuthType}
+
+// SourceDescriber is implemented by providers that can describe where they read a field from,
+// such as the environment variable or the config file and profile
+type SourceDescriber interface {
+	DescribeSource(field Field) string
+}
+
+// Explainer is implemented by providers that are composed of other providers
+type Explainer interface {
+	Explain() Explanation
+}
+
+// Explanation describes how each [Field] of a provider was resolved
+type Explanation []FieldExplanation
+
+// Field returns the explanation of a single field
+func (e Explanation) Field(field Field) (FieldExplanation, bool) {
+	for _, fe := range e {
+		if fe.Field == field {
+			return fe, true
+		}
+	}
+	return FieldExplanation{}, false
+}
+
+// FieldExplanation describes which provider supplied a [Field]
+type FieldExplanation struct {
+	Field Field
+	// Value is the resolved value. It is empty for [FieldPrivateKey].
+	Value string
+	// Provider supplied the value, it is nil when no provider did. For the OCI_REGION fallback of
+	// [common.ComposingConfigurationProvider] it is a provider of that region alone.
+	Provider common.ConfigurationProvider
+	// Index is the position of Provider in the composed providers, or -1 when the value did not come from one of them
+	Index int
+	// Source describes where Provider read the value from
+	Source string
+	// Skipped holds the errors of the providers that were consulted before Provider
+	Skipped []SkippedProvider
+}
+
+// Found reports whether a provider supplied the field. It depends on Provider rather than Index, since
+// the zero FieldExplanation returned by [Explanation.Field] has Index 0, and a value may come from a
+// provider outside the composed ones, such as the region provider of [CoherentConfigProviderWithRegion].
+func (fe FieldExplanation) Found() bool {
+	return fe.Provider != nil
+}
+
+// SkippedProvider is a provider that could not supply a [Field]
+type SkippedProvider struct {
+	Index    int
+	Provider common.ConfigurationProvider
+	Source   string
+	Err      error
+}
+
+// Explain returns where each [Field] of provider comes from. Providers implementing [Explainer],
+// such as those returned by [ComposingConfigProvider] and [DefaultConfigProvider], explain
+// every provider they are composed of; any other provider is explained on its own.
+func Explain(provider common.ConfigurationProvider) Explanation {
+	if e, ok := provider.(Explainer); ok {
+		return e.Explain()
+	}
+	return explainProviders([]common.ConfigurationProvider{provider})
+}
+
+func explainProviders(providers []common.ConfigurationProvider) Explanation {
+	explanation := make(Explanation, 0, len(Fields))
+	for _, field := range Fields {
+		explanation = append(explanation, explainField(providers, field))
+	}
+	return explanation
+}
+
+func explainField(providers []common.ConfigurationProvider, field Field) FieldExplanation {
+	fe := FieldExplanation{Field: field, Index: -1}
+	for i, provider := range providers {
+		value, err := resolveField(provider, field)
+		if err != nil {
+			fe.Skipped = append(fe.Skipped, SkippedProvider{Index: i, Provider: provider, Source: describeSource(provider, field), Err: err})
+			continue
+		}
+
+		fe.Value = value
+		fe.Provider = provider
+		fe.Index = i
+		fe.Source = describeSource(provider, field)
+		return fe
+	}
+
+	// mirrors the fallback of [common.ComposingConfigurationProvider]
+	if field == FieldRegion {
+		if value, ok := os.LookupEnv("OCI_REGION"); ok {
+			fe.Value = value
+			fe.Source = "environment variable OCI_REGION"
+			fe.Provider = describedProvider{common.NewRawConfigurationProvider("", "", value, "", "", nil), fe.Source}
+		}
+	}
+	return fe
+}
+
+func resolveField(provider common.ConfigurationProvider, field Field) (value string, err error) {
+	switch field {
+	case FieldTenancy:
+		return provider.TenancyOCID()
+	case FieldUser:
+		return provider.UserOCID()
+	case FieldFingerprint:
+		return provider.KeyFingerprint()
+	case FieldRegion:
+		return provider.Region()
+	case FieldKeyID:
+		return provider.KeyID()
+	case FieldPrivateKey:
+		_, err = provider.PrivateRSAKey()
+		return
+	case FieldAuthType:
+		var at common.AuthConfig
+		if at, err = provider.AuthType(); err == nil && at.AuthType == common.UnknownAuthenticationType {
+			err = ErrNoAuthType
+		}
+		return string(at.AuthType), err
+	}
+	return "", fmt.Errorf("unknown field %q", field)
+}
+
+func describeSource(provider common.ConfigurationProvider, field Field) string {
+	if d, ok := provider.(SourceDescriber); ok {
+		return d.DescribeSource(field)
+	}
+	return fmt.Sprintf("%T", provider)
+}
+
+// describedProvider labels a provider which does not implement [SourceDescriber]
+type describedProvider struct {
+	common.ConfigurationProvider
+	source string
+}
+
+func (p describedProvider) DescribeSource(Field) string {
+	return p.source
+}
+
+// Warm warms the labelled provider if it implements [Warmer]
+func (p describedProvider) Warm(ctx context.Context) error {
+	return Warm(ctx, p.ConfigurationProvider)
+}
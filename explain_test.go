@@ -0,0 +1,157 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+var _ = Describe("Explain", func() {
+	var privateKeyPath string
+
+	BeforeEach(func() {
+		privateKeyPath = createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+	})
+
+	Context("composing provider", func() {
+		var (
+			envProvider  common.ConfigurationProvider
+			fullProvider common.ConfigurationProvider
+			conf         common.ConfigurationProvider
+		)
+
+		BeforeEach(func() {
+			envProvider = OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{
+				EnvTenancy: testTenancy,
+				EnvRegion:  testRegion,
+			}))
+			fullProvider = OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{
+				EnvTenancy:     "other-" + testTenancy,
+				EnvUser:        testUser,
+				EnvFingerprint: testFingerprint,
+				EnvRegion:      "eu-frankfurt-1",
+				EnvKeyFile:     privateKeyPath,
+				EnvAuth:        string(ApiKeyType),
+			}))
+			conf = ComposingConfigProvider(envProvider, fullProvider)
+		})
+
+		It("names the winning provider and its source", func() {
+			explanation := Explain(conf)
+			Expect(explanation).To(HaveLen(len(Fields)))
+
+			tenancy, ok := explanation.Field(FieldTenancy)
+			Expect(ok).To(BeTrue())
+			Expect(tenancy.Found()).To(BeTrue())
+			Expect(tenancy.Value).To(Equal(testTenancy))
+			Expect(tenancy.Index).To(Equal(0))
+			Expect(tenancy.Provider).To(BeIdenticalTo(envProvider))
+			Expect(tenancy.Source).To(Equal("environment variable " + EnvTenancy))
+			Expect(tenancy.Skipped).To(BeEmpty())
+
+			key, _ := explanation.Field(FieldPrivateKey)
+			Expect(key.Index).To(Equal(1))
+			Expect(key.Value).To(BeEmpty())
+			Expect(key.Source).To(ContainSubstring(privateKeyPath))
+			Expect(key.Source).To(ContainSubstring(EnvKeyFile))
+		})
+
+		It("reports the errors of skipped providers", func() {
+			user, _ := Explain(conf).Field(FieldUser)
+			Expect(user.Value).To(Equal(testUser))
+			Expect(user.Index).To(Equal(1))
+			Expect(user.Skipped).To(HaveLen(1))
+			Expect(user.Skipped[0].Index).To(Equal(0))
+			Expect(user.Skipped[0].Provider).To(BeIdenticalTo(envProvider))
+			Expect(user.Skipped[0].Err).To(MatchError(&EnvError{EnvUser}))
+
+			at, _ := Explain(conf).Field(FieldAuthType)
+			Expect(at.Value).To(Equal(string(common.UserPrincipal)))
+			Expect(at.Skipped[0].Err).To(MatchError(&EnvError{EnvAuth}))
+		})
+
+		It("describes the source of the supplying provider", func() {
+			Expect(conf.(SourceDescriber).DescribeSource(FieldFingerprint)).To(Equal("environment variable " + EnvFingerprint))
+		})
+
+		It("reports when no provider supplies a field", func() {
+			fp, _ := Explain(ComposingConfigProvider(envProvider)).Field(FieldFingerprint)
+			Expect(fp.Found()).To(BeFalse())
+			Expect(fp.Index).To(Equal(-1))
+			Expect(fp.Provider).To(BeNil())
+			Expect(fp.Skipped).To(HaveLen(1))
+		})
+
+		It("finds the region in OCI_REGION when no provider supplies it", func() {
+			_ = os.Setenv("OCI_REGION", "eu-frankfurt-1")
+			noRegion := OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{EnvTenancy: testTenancy}))
+
+			region, _ := Explain(ComposingConfigProvider(noRegion)).Field(FieldRegion)
+			Expect(region.Found()).To(BeTrue())
+			Expect(region.Value).To(Equal("eu-frankfurt-1"))
+			Expect(region.Index).To(Equal(-1))
+			Expect(region.Source).To(Equal("environment variable OCI_REGION"))
+			Expect(region.Provider.Region()).To(Equal("eu-frankfurt-1"))
+		})
+	})
+
+	It("is only found when a provider supplied the field", func() {
+		Expect(FieldExplanation{}.Found()).To(BeFalse())
+		Expect(FieldExplanation{Index: -1, Provider: OciCliEnvironmentConfigurationProvider()}.Found()).To(BeTrue())
+	})
+
+	It("explains a single provider", func() {
+		region, _ := Explain(OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{EnvRegion: testRegion}))).Field(FieldRegion)
+		Expect(region.Value).To(Equal(testRegion))
+		Expect(region.Index).To(Equal(0))
+	})
+
+	Context("DefaultConfigProvider", func() {
+		BeforeEach(func() {
+			testCliConfigFileTmplData.TestKeyFile = privateKeyPath
+
+			b := &bytes.Buffer{}
+			_ = template.Must(template.New("").Parse(testCliConfigFileTmpl)).Execute(b, testCliConfigFileTmplData)
+			configFile := createTempFile(b.Bytes())
+			DeferCleanup(os.Remove, configFile)
+			_ = os.Setenv(EnvConfigFile, configFile)
+			_ = os.Setenv(EnvRegion, testRegion)
+		})
+
+		It("names the environment variable and the config file profile", func() {
+			explanation := Explain(DefaultConfigProvider())
+
+			region, _ := explanation.Field(FieldRegion)
+			Expect(region.Index).To(Equal(0))
+			Expect(region.Source).To(Equal("environment variable " + EnvRegion))
+
+			user, _ := explanation.Field(FieldUser)
+			Expect(user.Index).To(Equal(1))
+			Expect(user.Source).To(Equal("config file " + os.Getenv(EnvConfigFile) + " [test]"))
+			Expect(user.Skipped).To(HaveLen(1))
+			Expect(user.Skipped[0].Err).To(MatchError(&EnvError{EnvUser}))
+		})
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"log"
+	"os"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+)
+
+// FilePermissionPolicy decides what happens when a private key or security token file can be
+// accessed by users other than its owner, or is owned by another user
+type FilePermissionPolicy int
+
+const (
+	// IgnoreInsecureFiles reads insecure files without checking them
+	IgnoreInsecureFiles FilePermissionPolicy = iota
+	// WarnInsecureFiles logs a warning with [log.Printf] and reads the file
+	WarnInsecureFiles
+	// RejectInsecureFiles returns an [InsecureKeyFileError] instead of reading the file
+	RejectInsecureFiles
+)
+
+// checkFilePermissions applies the policy to the file at filePath
+func (policy FilePermissionPolicy) checkFilePermissions(filePath string) error {
+	if policy == IgnoreInsecureFiles {
+		return nil
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	reason := internal.InsecureFileReason(info)
+	if reason == "" {
+		return nil
+	}
+
+	insecureErr := &InsecureKeyFileError{Path: filePath, Mode: info.Mode(), Reason: reason}
+	if policy == WarnInsecureFiles {
+		log.Printf("WARNING: %v", insecureErr)
+		return nil
+	}
+	return insecureErr
+}
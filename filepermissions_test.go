@@ -0,0 +1,101 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+)
+
+var _ = Describe("FilePermissionPolicy", func() {
+	var (
+		privateKeyPath string
+		tokenFilePath  string
+		logOutput      *bytes.Buffer
+		env            map[string]string
+	)
+
+	BeforeEach(func() {
+		privateKeyPath = createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+		tokenFilePath = createTempFile([]byte(testSecurityToken))
+		DeferCleanup(os.Remove, tokenFilePath)
+
+		env = map[string]string{
+			EnvTenancy:           testTenancy,
+			EnvFingerprint:       testFingerprint,
+			EnvAuth:              string(SecurityTokenType),
+			EnvKeyFile:           privateKeyPath,
+			EnvSecurityTokenFile: tokenFilePath,
+		}
+
+		logOutput = &bytes.Buffer{}
+		log.SetOutput(logOutput)
+		DeferCleanup(log.SetOutput, os.Stderr)
+	})
+
+	DescribeTable("private key file",
+		func(mode fs.FileMode, policy FilePermissionPolicy, insecure, warned bool) {
+			Expect(os.Chmod(privateKeyPath, mode)).To(Succeed())
+
+			_, err := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithFilePermissionPolicy(policy)).PrivateRSAKey()
+			if insecure {
+				var insecureErr *InsecureKeyFileError
+				Expect(errors.As(err, &insecureErr)).To(BeTrue())
+				Expect(insecureErr.Path).To(Equal(privateKeyPath))
+				Expect(insecureErr.Mode.Perm()).To(Equal(mode))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			if warned {
+				Expect(logOutput.String()).To(ContainSubstring(privateKeyPath))
+			} else {
+				Expect(logOutput.String()).To(BeEmpty())
+			}
+		},
+		Entry("owner only, rejected", fs.FileMode(0600), RejectInsecureFiles, false, false),
+		Entry("read only owner, rejected", fs.FileMode(0400), RejectInsecureFiles, false, false),
+		Entry("group readable, rejected", fs.FileMode(0640), RejectInsecureFiles, true, false),
+		Entry("world readable, rejected", fs.FileMode(0644), RejectInsecureFiles, true, false),
+		Entry("world readable, warned", fs.FileMode(0644), WarnInsecureFiles, false, true),
+		Entry("owner only, warned", fs.FileMode(0600), WarnInsecureFiles, false, false),
+		Entry("world readable, ignored", fs.FileMode(0644), IgnoreInsecureFiles, false, false),
+	)
+
+	It("checks the security token file", func() {
+		Expect(os.Chmod(tokenFilePath, 0644)).To(Succeed())
+
+		_, err := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithFilePermissionPolicy(RejectInsecureFiles)).KeyID()
+		var insecureErr *InsecureKeyFileError
+		Expect(errors.As(err, &insecureErr)).To(BeTrue())
+		Expect(insecureErr.Path).To(Equal(tokenFilePath))
+		Expect(err.Error()).To(ContainSubstring("0644"))
+	})
+
+	It("ignores insecure files by default", func() {
+		Expect(os.Chmod(tokenFilePath, 0666)).To(Succeed())
+		Expect(OciCliEnvironmentConfigurationProvider(WithEnvMap(env)).KeyID()).To(Equal("ST$" + testSecurityToken))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// PublicKeyFingerprint returns the fingerprint OCI uses for an API signing key: the MD5 hash of the
+// DER encoded public key as colon separated hex, e.g. 12:34:56:78:90:ab:cd:ef:12:34:56:78:90:ab:cd:ef
+func PublicKeyFingerprint(key *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	sum := md5.Sum(der)
+	hexPairs := make([]string, len(sum))
+	for i, b := range sum {
+		hexPairs[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(hexPairs, ":"), nil
+}
+
+// VerifyFingerprint checks that the KeyFingerprint of provider belongs to its PrivateRSAKey, and
+// returns a [FingerprintMismatchError] if it does not
+func VerifyFingerprint(provider common.ConfigurationProvider) error {
+	fingerprint, err := provider.KeyFingerprint()
+	if err != nil {
+		return err
+	}
+
+	key, err := provider.PrivateRSAKey()
+	if err != nil {
+		return err
+	}
+	return verifyFingerprint(fingerprint, key)
+}
+
+func verifyFingerprint(fingerprint string, key *rsa.PrivateKey) error {
+	actual, err := PublicKeyFingerprint(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(fingerprint), actual) {
+		return &FingerprintMismatchError{Fingerprint: fingerprint, KeyFingerprint: actual}
+	}
+	return nil
+}
+
+// keyFingerprint returns the configured fingerprint, verified against the private key when
+// [WithFingerprintVerification] is given, or else the fingerprint derived from the private key when
+// [WithDerivedFingerprint] is given. notSet is the error for a fingerprint that is not configured.
+func (o *options) keyFingerprint(fingerprint string, ok bool, notSet error, privateKey func() (*rsa.PrivateKey, error)) (string, error) {
+	if !ok {
+		if !o.deriveFingerprint {
+			return "", notSet
+		}
+
+		key, err := privateKey()
+		if err != nil {
+			return "", errors.Join(notSet, err)
+		}
+		return PublicKeyFingerprint(&key.PublicKey)
+	}
+
+	if o.verifyFingerprint {
+		key, err := privateKey()
+		if err != nil {
+			return "", err
+		}
+		if err = verifyFingerprint(fingerprint, key); err != nil {
+			return "", err
+		}
+	}
+	return fingerprint, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"crypto/md5"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+var _ = Describe("Fingerprint", func() {
+	var (
+		fingerprint string
+		env         map[string]string
+	)
+
+	BeforeEach(func() {
+		der, _ := x509.MarshalPKIXPublicKey(&testPk.PublicKey)
+		sum := md5.Sum(der)
+		fingerprint = strings.ReplaceAll(fmt.Sprintf("% x", sum[:]), " ", ":")
+
+		privateKeyPath := createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+		env = map[string]string{
+			EnvTenancy: testTenancy,
+			EnvUser:    testUser,
+			EnvRegion:  testRegion,
+			EnvKeyFile: privateKeyPath,
+		}
+	})
+
+	It("computes the fingerprint of a public key", func() {
+		actual, err := PublicKeyFingerprint(&testPk.PublicKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(MatchRegexp(`^([0-9a-f]{2}:){15}[0-9a-f]{2}$`))
+		Expect(actual).To(Equal(fingerprint))
+	})
+
+	Context("VerifyFingerprint", func() {
+		It("accepts the fingerprint of the key", func() {
+			env[EnvFingerprint] = fingerprint
+			Expect(VerifyFingerprint(OciCliEnvironmentConfigurationProvider(WithEnvMap(env)))).To(Succeed())
+		})
+
+		It("reports a stale fingerprint", func() {
+			env[EnvFingerprint] = testFingerprint
+			err := VerifyFingerprint(OciCliEnvironmentConfigurationProvider(WithEnvMap(env)))
+
+			var mismatchErr *FingerprintMismatchError
+			Expect(errors.As(err, &mismatchErr)).To(BeTrue())
+			Expect(mismatchErr.Fingerprint).To(Equal(testFingerprint))
+			Expect(mismatchErr.KeyFingerprint).To(Equal(fingerprint))
+		})
+	})
+
+	When("verification is enabled", func() {
+		It("returns the matching fingerprint", func() {
+			env[EnvFingerprint] = fingerprint
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithFingerprintVerification())
+			Expect(conf.KeyFingerprint()).To(Equal(fingerprint))
+		})
+
+		It("does not have valid configuration with a stale fingerprint", func() {
+			env[EnvFingerprint] = testFingerprint
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithFingerprintVerification())
+
+			valid, err := common.IsConfigurationProviderValid(conf)
+			Expect(valid).To(BeFalse())
+			var mismatchErr *FingerprintMismatchError
+			Expect(errors.As(err, &mismatchErr)).To(BeTrue())
+		})
+	})
+
+	When("the fingerprint is derived", func() {
+		It("computes the fingerprint when it is not set", func() {
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithDerivedFingerprint())
+			Expect(conf.KeyFingerprint()).To(Equal(fingerprint))
+
+			valid, err := common.IsConfigurationProviderValid(conf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		})
+
+		It("prefers the fingerprint environment variable", func() {
+			env[EnvFingerprint] = testFingerprint
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithDerivedFingerprint())
+			Expect(conf.KeyFingerprint()).To(Equal(testFingerprint))
+		})
+
+		It("reports the missing fingerprint when there is no key", func() {
+			delete(env, EnvKeyFile)
+			_, err := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithDerivedFingerprint()).KeyFingerprint()
+			Expect(err).To(MatchError(ContainSubstring(EnvFingerprint)))
+		})
+	})
+})
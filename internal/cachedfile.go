@@ -0,0 +1,86 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	cachedFileReadAttempts = 5
+	cachedFileRetryDelay   = 10 * time.Millisecond
+)
+
+// CachedFile reads a file and keeps its content until the file changes. A change is detected by
+// the size, modification time and identity of the file, so replacing it with an atomic rename is
+// picked up as well as rewriting it in place.
+//
+// The content is only accepted once the file was not changed while reading it and is not empty,
+// so a file that is being written is never returned partially.
+type CachedFile struct {
+	mu      sync.Mutex
+	path    string
+	info    os.FileInfo
+	content []byte
+}
+
+// Read returns the content of the file at path, reading it again only if it changed since the last read
+func (f *CachedFile) Read(path string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		before, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.info != nil && f.path == path && sameFileInfo(f.info, before) {
+			return f.content, nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if sameFileInfo(before, after) && int64(len(content)) == after.Size() && len(content) > 0 {
+			f.path, f.info, f.content = path, after, content
+			return content, nil
+		}
+
+		if attempt == cachedFileReadAttempts {
+			if len(content) == 0 {
+				return nil, fmt.Errorf("%s is empty", path)
+			}
+			return nil, fmt.Errorf("%s changed while it was read", path)
+		}
+		time.Sleep(cachedFileRetryDelay)
+	}
+}
+
+func sameFileInfo(a, b os.FileInfo) bool {
+	return os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// InsecureFileReason returns why a file holding secrets is insecure, or "" if it is not.
+// A file is insecure if its group or others have any permissions, or another user owns it.
+func InsecureFileReason(info os.FileInfo) string {
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Sprintf("permissions %04o allow access by group or others", perm)
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if uid := os.Geteuid(); int(stat.Uid) != uid {
+			return fmt.Sprintf("owned by uid %d instead of %d", stat.Uid, uid)
+		}
+	}
+	return ""
+}
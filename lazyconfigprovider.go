@@ -17,7 +17,11 @@ limitations under the License.
 package ocep
 
 import (
+	"context"
 	"crypto/rsa"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/oracle/oci-go-sdk/v65/common"
 )
@@ -25,69 +29,301 @@ import (
 // LazyConfigProvider returns a [common.ConfigurationProvider] that is initialized one time
 // by calling the func argument. The initialization func is only called if the provider methods are
 // called.
-func LazyConfigProvider(providerFunc func() (common.ConfigurationProvider, error)) common.ConfigurationProvider {
-	return &lazyProvider{providerFunc: providerFunc}
+//
+// Initialization is safe for concurrent use; concurrent callers wait for a single call of the func.
+// By default a failed initialization is tried again on the next method call, which can be limited
+// with [WithCachedFailure], [WithMaxAttempts] and [WithRetryBackoff].
+func LazyConfigProvider(providerFunc func() (common.ConfigurationProvider, error), opts ...LazyOption) common.ConfigurationProvider {
+	return newLazyProvider(func(context.Context) (common.ConfigurationProvider, error) {
+		return providerFunc()
+	}, 0, opts...)
 }
 
+// LazyConfigProviderWithContext is like [LazyConfigProvider], except the initialization func is given
+// a context that is cancelled after timeout, and the provider methods stop waiting for it then and
+// return an [InitTimeoutError]. Initialization that is still running when a method gives up is waited
+// for again by the next method call instead of being started anew.
+//
+// A timeout of 0 waits for initialization as long as it takes.
+func LazyConfigProviderWithContext(providerFunc func(context.Context) (common.ConfigurationProvider, error), timeout time.Duration, opts ...LazyOption) common.ConfigurationProvider {
+	return newLazyProvider(providerFunc, timeout, opts...)
+}
+
+func newLazyProvider(providerFunc func(context.Context) (common.ConfigurationProvider, error), timeout time.Duration, opts ...LazyOption) *lazyProvider {
+	p := &lazyProvider{providerFunc: providerFunc, timeout: timeout}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.warmUp {
+		go func() { _ = p.Warm(context.Background()) }()
+	}
+	return p
+}
+
+// Warmer is implemented by providers that can be initialized ahead of their first use
+type Warmer interface {
+	// Warm starts initialization if it has not started yet and waits until it finishes or ctx is done
+	Warm(ctx context.Context) error
+}
+
+// Warm initializes provider if it implements [Warmer] and waits until it is ready or ctx is done.
+// This allows e.g. a health check to wait for the credentials of a [LazyConfigProvider].
+func Warm(ctx context.Context, provider common.ConfigurationProvider) error {
+	if w, ok := provider.(Warmer); ok {
+		return w.Warm(ctx)
+	}
+	return nil
+}
+
+// LazyOption configures a [LazyConfigProvider]
+type LazyOption func(*lazyProvider)
+
+// WithCachedFailure returns the error of the first failed initialization from then on,
+// instead of trying again
+func WithCachedFailure() LazyOption {
+	return WithMaxAttempts(1)
+}
+
+// WithMaxAttempts limits the number of times initialization is tried. Once they are used up the
+// error of the last attempt is returned.
+func WithMaxAttempts(attempts int) LazyOption {
+	return func(p *lazyProvider) {
+		p.maxAttempts = attempts
+	}
+}
+
+// WithRetryBackoff waits before trying a failed initialization again, starting with initial and
+// doubling after each failure up to maxBackoff. Calls made while waiting return the error of the
+// last attempt.
+func WithRetryBackoff(initial, maxBackoff time.Duration) LazyOption {
+	return func(p *lazyProvider) {
+		p.initialBackoff = initial
+		p.maxBackoff = maxBackoff
+	}
+}
+
+// WithWarmUp starts initialization in the background as soon as the provider is created, so the
+// first method call does not have to wait for it. Use [Warm] to wait until it is ready.
+func WithWarmUp() LazyOption {
+	return func(p *lazyProvider) {
+		p.warmUp = true
+	}
+}
+
+var errLazyInitPanicked = errors.New("lazy provider initialization panicked")
+
 type lazyProvider struct {
-	providerFunc func() (common.ConfigurationProvider, error)
-	common.ConfigurationProvider
+	providerFunc   func(context.Context) (common.ConfigurationProvider, error)
+	timeout        time.Duration
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	warmUp         bool
+
+	mu          sync.Mutex
+	provider    common.ConfigurationProvider
+	err         error
+	attempts    int
+	nextAttempt time.Time
+	call        *lazyCall
+}
+
+// lazyCall is a running initialization, done is closed once it finished
+type lazyCall struct {
+	done     chan struct{}
+	provider common.ConfigurationProvider
+	err      error
 }
 
-func (p *lazyProvider) initProvider() (err error) {
-	if p.ConfigurationProvider == nil {
-		if p.ConfigurationProvider, err = p.providerFunc(); err != nil {
-			p.ConfigurationProvider = nil
+func (p *lazyProvider) initProvider() (common.ConfigurationProvider, error) {
+	call, started, provider, err := p.startInit()
+	if call == nil {
+		return provider, err
+	}
+
+	if p.timeout <= 0 {
+		if started {
+			p.runInit(call)
 		}
+		<-call.done
+		return call.provider, call.err
+	}
+
+	if started {
+		go p.runInit(call)
+	}
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case <-call.done:
+		return call.provider, call.err
+	case <-timer.C:
+		return nil, &InitTimeoutError{Timeout: p.timeout}
 	}
-	return
+}
+
+// Warm starts initialization in the background if it has not started yet, and waits until it
+// finishes or ctx is done. The initialization itself is not cancelled with ctx.
+func (p *lazyProvider) Warm(ctx context.Context) error {
+	call, started, _, err := p.startInit()
+	if call == nil {
+		return err
+	}
+
+	if started {
+		go p.runInit(call)
+	}
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startInit returns the running initialization, starting a new one if needed. When no initialization
+// is needed, it returns the provider or the error to return instead.
+func (p *lazyProvider) startInit() (call *lazyCall, started bool, provider common.ConfigurationProvider, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return nil, false, p.provider, nil
+	}
+
+	if p.call != nil {
+		return p.call, false, nil, nil
+	}
+
+	if p.err != nil {
+		if p.maxAttempts > 0 && p.attempts >= p.maxAttempts {
+			return nil, false, nil, p.err
+		}
+		if time.Now().Before(p.nextAttempt) {
+			return nil, false, nil, p.err
+		}
+	}
+
+	p.attempts++
+	p.call = &lazyCall{done: make(chan struct{})}
+	return p.call, true, nil, nil
+}
+
+func (p *lazyProvider) runInit(call *lazyCall) {
+	ctx := context.Background()
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	provider, err := common.ConfigurationProvider(nil), errLazyInitPanicked
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			err = &InitTimeoutError{Timeout: p.timeout, Err: err}
+		}
+		p.finishInit(call, provider, err)
+	}()
+
+	provider, err = p.providerFunc(ctx)
+}
+
+func (p *lazyProvider) finishInit(call *lazyCall, provider common.ConfigurationProvider, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		provider = nil
+		p.err = err
+		p.nextAttempt = time.Now().Add(p.backoff())
+	} else {
+		p.provider, p.err = provider, nil
+	}
+
+	p.call = nil
+	call.provider, call.err = provider, err
+	close(call.done)
+}
+
+// backoff returns the time to wait after the current number of failed attempts
+func (p *lazyProvider) backoff() time.Duration {
+	backoff := p.initialBackoff
+	for i := 1; i < p.attempts && backoff < p.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if p.maxBackoff > 0 && backoff > p.maxBackoff {
+		backoff = p.maxBackoff
+	}
+	return backoff
 }
 
 func (p *lazyProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
-	if err := p.initProvider(); err != nil {
+	provider, err := p.initProvider()
+	if err != nil {
 		return nil, err
 	}
-	return p.ConfigurationProvider.PrivateRSAKey()
+	return provider.PrivateRSAKey()
 }
 
 func (p *lazyProvider) KeyID() (string, error) {
-	if err := p.initProvider(); err != nil {
+	provider, err := p.initProvider()
+	if err != nil {
 		return "", err
 	}
-	return p.ConfigurationProvider.KeyID()
+	return provider.KeyID()
 }
 
 func (p *lazyProvider) TenancyOCID() (string, error) {
-	if err := p.initProvider(); err != nil {
+	provider, err := p.initProvider()
+	if err != nil {
 		return "", err
 	}
-	return p.ConfigurationProvider.TenancyOCID()
+	return provider.TenancyOCID()
 }
 
 func (p *lazyProvider) UserOCID() (string, error) {
-	if err := p.initProvider(); err != nil {
+	provider, err := p.initProvider()
+	if err != nil {
 		return "", err
 	}
-	return p.ConfigurationProvider.UserOCID()
+	return provider.UserOCID()
 }
 
 func (p *lazyProvider) KeyFingerprint() (string, error) {
-	if err := p.initProvider(); err != nil {
+	provider, err := p.initProvider()
+	if err != nil {
 		return "", err
 	}
-	return p.ConfigurationProvider.KeyFingerprint()
+	return provider.KeyFingerprint()
 }
 
 func (p *lazyProvider) Region() (string, error) {
-	if err := p.initProvider(); err != nil {
+	provider, err := p.initProvider()
+	if err != nil {
 		return "", err
 	}
-	return p.ConfigurationProvider.Region()
+	return provider.Region()
 }
 
 func (p *lazyProvider) AuthType() (common.AuthConfig, error) {
-	if err := p.initProvider(); err != nil {
+	provider, err := p.initProvider()
+	if err != nil {
 		return common.AuthConfig{AuthType: common.UnknownAuthenticationType}, err
 	}
-	return p.ConfigurationProvider.AuthType()
+	return provider.AuthType()
+}
+
+// DescribeSource describes the source of the initialized provider
+func (p *lazyProvider) DescribeSource(field Field) string {
+	p.mu.Lock()
+	provider := p.provider
+	p.mu.Unlock()
+
+	if provider == nil {
+		return "uninitialized lazy provider"
+	}
+	return describeSource(provider, field)
 }
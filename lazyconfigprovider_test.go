@@ -17,8 +17,12 @@ limitations under the License.
 package ocep_test
 
 import (
+	"context"
 	"crypto/rsa"
 	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -93,6 +97,224 @@ var _ = Describe("LazyConfigProvider", func() {
 	})
 })
 
+var _ = Describe("LazyConfigProvider retries", func() {
+	var (
+		initCounter  atomic.Int32
+		initError    error
+		providerFunc = func() (common.ConfigurationProvider, error) {
+			initCounter.Add(1)
+			if initError != nil {
+				return nil, initError
+			}
+			return &noOpProvider{}, nil
+		}
+	)
+
+	BeforeEach(func() {
+		initCounter.Store(0)
+		initError = errors.New("some error")
+	})
+
+	It("initializes once for concurrent callers", func() {
+		initError = nil
+		release := make(chan struct{})
+		conf := ocep.LazyConfigProvider(func() (common.ConfigurationProvider, error) {
+			<-release
+			return providerFunc()
+		})
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+				_, err := conf.TenancyOCID()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		close(release)
+		wg.Wait()
+
+		Expect(initCounter.Load()).To(Equal(int32(1)))
+	})
+
+	It("tries again on every call by default", func() {
+		conf := ocep.LazyConfigProvider(providerFunc)
+		_, _ = conf.TenancyOCID()
+		_, _ = conf.Region()
+		Expect(initCounter.Load()).To(Equal(int32(2)))
+	})
+
+	It("caches the failure", func() {
+		conf := ocep.LazyConfigProvider(providerFunc, ocep.WithCachedFailure())
+		for range 3 {
+			_, err := conf.TenancyOCID()
+			Expect(err).To(MatchError(initError))
+		}
+		Expect(initCounter.Load()).To(Equal(int32(1)))
+	})
+
+	It("limits the number of attempts", func() {
+		conf := ocep.LazyConfigProvider(providerFunc, ocep.WithMaxAttempts(3))
+		for range 5 {
+			_, err := conf.KeyID()
+			Expect(err).To(MatchError(initError))
+		}
+		Expect(initCounter.Load()).To(Equal(int32(3)))
+	})
+
+	It("waits between attempts", func() {
+		conf := ocep.LazyConfigProvider(providerFunc, ocep.WithRetryBackoff(50*time.Millisecond, time.Second))
+		_, _ = conf.TenancyOCID()
+		_, err := conf.TenancyOCID()
+		Expect(err).To(MatchError(initError))
+		Expect(initCounter.Load()).To(Equal(int32(1)))
+
+		initError = nil
+		Eventually(conf.TenancyOCID).WithPolling(10 * time.Millisecond).Should(BeEmpty())
+		Expect(initCounter.Load()).To(Equal(int32(2)))
+	})
+
+	It("doubles the backoff after each failure", func() {
+		conf := ocep.LazyConfigProvider(providerFunc, ocep.WithRetryBackoff(20*time.Millisecond, time.Second))
+		tryInit := func() int32 {
+			_, _ = conf.TenancyOCID()
+			return initCounter.Load()
+		}
+
+		start := time.Now()
+		Expect(tryInit()).To(Equal(int32(1)))
+		Eventually(tryInit).WithPolling(5 * time.Millisecond).Should(Equal(int32(2)))
+		Eventually(tryInit).WithPolling(5 * time.Millisecond).Should(Equal(int32(3)))
+		Expect(time.Since(start)).To(BeNumerically(">=", 60*time.Millisecond))
+	})
+})
+
+var _ = Describe("LazyConfigProviderWithContext", func() {
+	It("passes a context with the timeout", func() {
+		conf := ocep.LazyConfigProviderWithContext(func(ctx context.Context) (common.ConfigurationProvider, error) {
+			deadline, ok := ctx.Deadline()
+			Expect(ok).To(BeTrue())
+			Expect(deadline).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+			return &noOpProvider{}, nil
+		}, time.Minute)
+
+		_, err := conf.Region()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns a timeout error when the context is cancelled", func() {
+		conf := ocep.LazyConfigProviderWithContext(func(ctx context.Context) (common.ConfigurationProvider, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, 20*time.Millisecond)
+
+		_, err := conf.TenancyOCID()
+		var timeoutErr *ocep.InitTimeoutError
+		Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+		Expect(timeoutErr.Timeout).To(Equal(20 * time.Millisecond))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		_, err = conf.TenancyOCID()
+		Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+	})
+
+	It("distinguishes configuration errors from timeouts", func() {
+		configErr := errors.New("bad configuration")
+		conf := ocep.LazyConfigProviderWithContext(func(context.Context) (common.ConfigurationProvider, error) {
+			return nil, configErr
+		}, time.Minute)
+
+		_, err := conf.TenancyOCID()
+		Expect(err).To(MatchError(configErr))
+		var timeoutErr *ocep.InitTimeoutError
+		Expect(errors.As(err, &timeoutErr)).To(BeFalse())
+	})
+
+	It("stops waiting for initialization that ignores the context", func() {
+		var initCounter atomic.Int32
+		release := make(chan struct{})
+		conf := ocep.LazyConfigProviderWithContext(func(context.Context) (common.ConfigurationProvider, error) {
+			initCounter.Add(1)
+			<-release
+			return &noOpProvider{}, nil
+		}, 20*time.Millisecond)
+
+		_, err := conf.TenancyOCID()
+		var timeoutErr *ocep.InitTimeoutError
+		Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+
+		close(release)
+		Eventually(conf.TenancyOCID).Should(BeEmpty())
+		Expect(initCounter.Load()).To(Equal(int32(1)))
+	})
+})
+
+var _ = Describe("LazyConfigProvider warm up", func() {
+	var (
+		initCounter *atomic.Int32
+		release     chan struct{}
+	)
+
+	// providerFunc captures the state of the current spec, as the initialization can outlive it
+	providerFunc := func(initError error) func() (common.ConfigurationProvider, error) {
+		counter, released := initCounter, release
+		return func() (common.ConfigurationProvider, error) {
+			counter.Add(1)
+			<-released
+			if initError != nil {
+				return nil, initError
+			}
+			return &noOpProvider{}, nil
+		}
+	}
+
+	BeforeEach(func() {
+		initCounter = &atomic.Int32{}
+		release = make(chan struct{})
+	})
+
+	It("waits until the provider is ready", func() {
+		conf := ocep.LazyConfigProvider(providerFunc(nil))
+		close(release)
+		Expect(ocep.Warm(context.Background(), conf)).To(Succeed())
+		Expect(initCounter.Load()).To(Equal(int32(1)))
+
+		_, _ = conf.TenancyOCID()
+		Expect(initCounter.Load()).To(Equal(int32(1)))
+	})
+
+	It("returns the initialization error", func() {
+		initError := errors.New("some error")
+		close(release)
+		Expect(ocep.Warm(context.Background(), ocep.LazyConfigProvider(providerFunc(initError)))).To(MatchError(initError))
+	})
+
+	It("stops waiting when the context is done", func() {
+		DeferCleanup(func() { close(release) })
+		conf := ocep.LazyConfigProvider(providerFunc(nil))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		Expect(ocep.Warm(ctx, conf)).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("starts initialization in the background", func() {
+		conf := ocep.LazyConfigProvider(providerFunc(nil), ocep.WithWarmUp())
+		Eventually(initCounter.Load).Should(Equal(int32(1)))
+
+		close(release)
+		Expect(conf.(ocep.Warmer).Warm(context.Background())).To(Succeed())
+		_, _ = conf.KeyID()
+		Expect(initCounter.Load()).To(Equal(int32(1)))
+	})
+
+	It("ignores providers that are not lazy", func() {
+		Expect(ocep.Warm(context.Background(), &noOpProvider{})).To(Succeed())
+	})
+})
+
 type noOpProvider struct{}
 
 func (n noOpProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
@@ -26,48 +26,96 @@ import (
 	"crypto/rsa"
 	"errors"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/ontariosystems/oci-cli-env-provider/ocid"
 	"github.com/oracle/oci-go-sdk/v65/common"
 )
 
+// Values of OCI_CLI_AUTH
 const (
-	ApiKeyType        common.AuthenticationType = "api_key"
-	SecurityTokenType common.AuthenticationType = "security_token"
+	ApiKeyType              common.AuthenticationType = "api_key"
+	SecurityTokenType       common.AuthenticationType = "security_token"
+	InstancePrincipalType   common.AuthenticationType = "instance_principal"
+	ResourcePrincipalType   common.AuthenticationType = "resource_principal"
+	OkeWorkloadIdentityType common.AuthenticationType = "oke_workload_identity"
+	InstanceOboUserType     common.AuthenticationType = "instance_obo_user"
+)
+
+// OCID resource types of OCI_CLI_TENANCY and OCI_CLI_USER
+const (
+	tenancyResourceType = "tenancy"
+	userResourceType    = "user"
 )
 
 // OciCliEnvironmentConfigurationProvider returns a [common.ConfigurationProvider] that
 // gets values from [oci-cli environment variables]
 //
+// The variables are read from the process environment unless another source is given
+// with [WithLookupEnv] or [WithEnvMap].
+//
 // [oci-cli environment variables]: https://docs.oracle.com/en-us/iaas/Content/API/SDKDocs/clienvironmentvariables.htm
-func OciCliEnvironmentConfigurationProvider() common.ConfigurationProvider {
-	return &ociCliEnvProvider{}
+func OciCliEnvironmentConfigurationProvider(opts ...Option) common.ConfigurationProvider {
+	return newOciCliEnvProvider(newOptions(opts...))
 }
 
-type ociCliEnvProvider struct{}
+func newOciCliEnvProvider(o *options) *ociCliEnvProvider {
+	return &ociCliEnvProvider{options: o}
+}
+
+type ociCliEnvProvider struct {
+	*options
+	tokenFile securityTokenFile
+	keyFile   privateKeyFile
+
+	passphraseFile internal.CachedFile
+}
 
+func (p *ociCliEnvProvider) getEnv(key string) string {
+	value, _ := p.lookupEnv(key)
+	return value
+}
+
+// Passphrase returns the passphrase of the private key. It returns "" when the passphrase cannot be read,
+// for example when OCI_CLI_PASSPHRASE_FILE does not exist; PrivateRSAKey reports that error instead.
 func (p *ociCliEnvProvider) Passphrase() string {
-	return os.Getenv(EnvPassphrase)
+	passphrase, _ := p.passphrase()
+	return passphrase
 }
 
-func (p *ociCliEnvProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
-	passphrase := p.Passphrase()
+// passphrase reads the passphrase from the [PassphraseSource] option, or else the file in
+// OCI_CLI_PASSPHRASE_FILE, or else OCI_CLI_PASSPHRASE
+func (p *ociCliEnvProvider) passphrase() (string, error) {
+	if p.passphraseSource != nil {
+		return p.passphraseSource()
+	}
 
-	if value, ok := os.LookupEnv(EnvKeyContent); ok {
-		return common.PrivateKeyFromBytesWithPassword([]byte(value), []byte(passphrase))
+	if value, ok := p.lookupEnv(EnvPassphraseFile); ok {
+		return readPassphraseFile(&p.passphraseFile, value)
 	}
+	return p.getEnv(EnvPassphrase), nil
+}
 
-	if value, ok := os.LookupEnv(EnvKeyFile); ok {
-		content, err := os.ReadFile(internal.ExpandPath(value))
-		if err != nil {
-			return nil, err
-		}
+// PrivateRSAKey reads the key in OCI_CLI_KEY_CONTENT, or else the file in OCI_CLI_KEY_FILE. The passphrase
+// is only read once one of them is set.
+func (p *ociCliEnvProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
+	content, contentOk := p.lookupEnv(EnvKeyContent)
+	keyPath, fileOk := p.lookupEnv(EnvKeyFile)
+	if !contentOk && !fileOk {
+		return nil, errors.Join(&EnvError{EnvKeyContent}, &EnvError{EnvKeyFile})
+	}
 
-		return common.PrivateKeyFromBytesWithPassword(content, []byte(passphrase))
+	passphrase, err := p.passphrase()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, errors.Join(&EnvError{EnvKeyContent}, &EnvError{EnvKeyFile})
+	if contentOk {
+		return p.keyFile.cache.parse(EnvKeyContent, []byte(content), passphrase)
+	}
+	return p.keyFile.read(keyPath, passphrase, p.filePermissions)
 }
 
 func (p *ociCliEnvProvider) KeyID() (keyID string, err error) {
@@ -85,6 +133,11 @@ func (p *ociCliEnvProvider) KeyID() (keyID string, err error) {
 	if err == nil {
 		return fmt.Sprintf("%s/%s/%s", tenancy, user, fingerprint), nil
 	}
+	// only an unset user falls through to the other kinds of key, not a malformed one
+	var envErr *EnvError
+	if !errors.As(err, &envErr) {
+		return
+	}
 
 	at, err := p.AuthType()
 	if err != nil {
@@ -92,13 +145,8 @@ func (p *ociCliEnvProvider) KeyID() (keyID string, err error) {
 	}
 	switch at.AuthType {
 	case SecurityTokenType:
-		tokenPath, ok := os.LookupEnv(EnvSecurityTokenFile)
-		if !ok {
-			return "", &EnvError{EnvSecurityTokenFile}
-		}
-
-		var token []byte
-		if token, err = os.ReadFile(internal.ExpandPath(tokenPath)); err != nil {
+		var token string
+		if token, err = p.securityToken(); err != nil {
 			return
 		}
 		keyID = fmt.Sprintf("ST$%s", token)
@@ -110,47 +158,126 @@ func (p *ociCliEnvProvider) KeyID() (keyID string, err error) {
 }
 
 func (p *ociCliEnvProvider) TenancyOCID() (string, error) {
-	value, ok := os.LookupEnv(EnvTenancy)
-	if !ok {
-		return "", &EnvError{EnvTenancy}
-	}
-	return value, nil
+	return p.getOCID(EnvTenancy, tenancyResourceType)
 }
 
 func (p *ociCliEnvProvider) UserOCID() (string, error) {
-	value, ok := os.LookupEnv(EnvUser)
+	return p.getOCID(EnvUser, userResourceType)
+}
+
+func (p *ociCliEnvProvider) getOCID(envVar, resourceType string) (string, error) {
+	value, ok := p.lookupEnv(envVar)
 	if !ok {
-		return "", &EnvError{EnvUser}
+		return "", &EnvError{envVar}
+	}
+	if _, err := ocid.ParseType(value, resourceType); err != nil {
+		return "", &InvalidOCIDError{EnvVar: envVar, ResourceType: resourceType, Err: err}
 	}
 	return value, nil
 }
 
 func (p *ociCliEnvProvider) KeyFingerprint() (string, error) {
-	value, ok := os.LookupEnv(EnvFingerprint)
-	if !ok {
-		return "", &EnvError{EnvFingerprint}
-	}
-	return value, nil
+	value, ok := p.lookupEnv(EnvFingerprint)
+	return p.keyFingerprint(value, ok, &EnvError{EnvFingerprint}, p.PrivateRSAKey)
 }
 
 func (p *ociCliEnvProvider) Region() (string, error) {
-	value, ok := os.LookupEnv(EnvRegion)
+	value, ok := p.lookupEnv(EnvRegion)
 	if !ok {
 		return "", &EnvError{EnvRegion}
 	}
-	return value, nil
+	return p.resolveRegion(EnvRegion, value)
 }
 
 func (p *ociCliEnvProvider) AuthType() (common.AuthConfig, error) {
-	value, ok := os.LookupEnv(EnvAuth)
+	value, ok := p.lookupEnv(EnvAuth)
 	if !ok {
 		return common.AuthConfig{AuthType: common.UnknownAuthenticationType}, &EnvError{EnvAuth}
 	}
 
-	switch at := common.AuthenticationType(value); at {
+	return authConfig(common.AuthenticationType(value)), nil
+}
+
+// authConfig maps a value of OCI_CLI_AUTH to the oci-go-sdk authentication type
+func authConfig(at common.AuthenticationType) common.AuthConfig {
+	switch at {
 	case ApiKeyType:
-		return common.AuthConfig{AuthType: common.UserPrincipal}, nil
+		return common.AuthConfig{AuthType: common.UserPrincipal}
+	case InstancePrincipalType:
+		return common.AuthConfig{AuthType: common.InstancePrincipal}
+	case InstanceOboUserType:
+		return common.AuthConfig{AuthType: common.InstancePrincipalDelegationToken}
 	default:
-		return common.AuthConfig{AuthType: at}, nil
+		return common.AuthConfig{AuthType: at}
+	}
+}
+
+// DelegationToken returns the token in OCI_CLI_DELEGATION_TOKEN_FILE
+func (p *ociCliEnvProvider) DelegationToken() (string, error) {
+	tokenPath, ok := p.lookupEnv(EnvDelegationTokenFile)
+	if !ok {
+		return "", &EnvError{EnvDelegationTokenFile}
+	}
+	return readDelegationToken(tokenPath)
+}
+
+// SecurityTokenExpiry returns the expiry of the token in OCI_CLI_SECURITY_TOKEN_FILE
+func (p *ociCliEnvProvider) SecurityTokenExpiry() (time.Time, error) {
+	tokenPath, ok := p.lookupEnv(EnvSecurityTokenFile)
+	if !ok {
+		return time.Time{}, &EnvError{EnvSecurityTokenFile}
+	}
+
+	return p.tokenFile.expiry(tokenPath, p.filePermissions)
+}
+
+// securityToken reads the token in OCI_CLI_SECURITY_TOKEN_FILE, refreshing it first if it
+// expires within the refresh margin
+func (p *ociCliEnvProvider) securityToken() (string, error) {
+	tokenPath, ok := p.lookupEnv(EnvSecurityTokenFile)
+	if !ok {
+		return "", &EnvError{EnvSecurityTokenFile}
+	}
+
+	return p.tokenFile.token(tokenPath, p.options)
+}
+
+// DescribeSource names the environment variables the field is read from
+func (p *ociCliEnvProvider) DescribeSource(field Field) string {
+	switch field {
+	case FieldTenancy:
+		return envSource(EnvTenancy)
+	case FieldUser:
+		return envSource(EnvUser)
+	case FieldFingerprint:
+		if _, ok := p.lookupEnv(EnvFingerprint); !ok && p.deriveFingerprint {
+			return "derived from the private key in " + p.DescribeSource(FieldPrivateKey)
+		}
+		return envSource(EnvFingerprint)
+	case FieldRegion:
+		return envSource(EnvRegion)
+	case FieldAuthType:
+		return envSource(EnvAuth)
+	case FieldPrivateKey:
+		if _, ok := p.lookupEnv(EnvKeyContent); ok {
+			return envSource(EnvKeyContent)
+		}
+		if value, ok := p.lookupEnv(EnvKeyFile); ok {
+			return fmt.Sprintf("file %s from %s", internal.ExpandPath(value), envSource(EnvKeyFile))
+		}
+		return envSource(EnvKeyContent, EnvKeyFile)
+	case FieldKeyID:
+		if _, ok := p.lookupEnv(EnvUser); ok {
+			return envSource(EnvTenancy, EnvUser, EnvFingerprint)
+		}
+		return envSource(EnvTenancy, EnvFingerprint, EnvSecurityTokenFile)
+	}
+	return "environment"
+}
+
+func envSource(envVars ...string) string {
+	if len(envVars) == 1 {
+		return "environment variable " + envVars[0]
 	}
+	return "environment variables " + strings.Join(envVars, ", ")
 }
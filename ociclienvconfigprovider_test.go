@@ -21,6 +21,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -143,6 +144,118 @@ var _ = Describe("OciCliEnvironmentConfigurationProvider", func() {
 		})
 	})
 
+	Context("with an environment map", func() {
+		var env map[string]string
+
+		BeforeEach(func() {
+			privateKeyPath = createTempFile(testPrivateKeyConf)
+			env = map[string]string{
+				EnvUser:        testUser,
+				EnvTenancy:     testTenancy,
+				EnvFingerprint: testFingerprint,
+				EnvRegion:      testRegion,
+				EnvKeyFile:     privateKeyPath,
+				EnvAuth:        string(ApiKeyType),
+			}
+		})
+
+		It("has valid configuration", func() {
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			valid, err := common.IsConfigurationProviderValid(conf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+
+			at, err := conf.AuthType()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(at.AuthType).To(Equal(common.UserPrincipal))
+		})
+
+		It("ignores the process environment", func() {
+			_ = os.Setenv(EnvRegion, "process-region")
+			delete(env, EnvTenancy)
+			_ = os.Setenv(EnvTenancy, testTenancy)
+
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			Expect(conf.Region()).To(Equal(testRegion))
+			_, err := conf.TenancyOCID()
+			Expect(err).To(MatchError(&EnvError{EnvTenancy}))
+		})
+
+		It("reads encrypted key content and passphrase from the map", func() {
+			delete(env, EnvKeyFile)
+			env[EnvKeyContent] = string(testEncryptedPrivateKeyConf)
+			env[EnvPassphrase] = testPassphrase
+
+			conf := OciCliEnvironmentConfigurationProvider(WithLookupEnv(EnvMap(env)))
+			key, err := conf.PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.Equal(testPk)).To(BeTrue())
+		})
+
+		It("reads the security token file from the map", func() {
+			tokenFilePath := createTempFile([]byte(testSecurityToken))
+			DeferCleanup(os.Remove, tokenFilePath)
+			delete(env, EnvUser)
+			env[EnvAuth] = string(SecurityTokenType)
+			env[EnvSecurityTokenFile] = tokenFilePath
+
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			Expect(conf.KeyID()).To(Equal("ST$" + testSecurityToken))
+		})
+	})
+
+	Context("private key cache", func() {
+		var env map[string]string
+
+		BeforeEach(func() {
+			privateKeyPath = createTempFile(testEncryptedPrivateKeyConf)
+			env = map[string]string{EnvKeyFile: privateKeyPath, EnvPassphrase: testPassphrase}
+		})
+
+		It("does not parse an unchanged key again", func() {
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			first, err := conf.PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conf.PrivateRSAKey()).To(BeIdenticalTo(first))
+		})
+
+		It("parses the key again when the file changes", func() {
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			first, err := conf.PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+
+			otherPk, _ := rsa.GenerateKey(rand.Reader, 2048)
+			_ = os.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(otherPk)}), 0600)
+
+			second, err := conf.PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second).ToNot(BeIdenticalTo(first))
+			Expect(second.Equal(otherPk)).To(BeTrue())
+		})
+
+		It("parses the key again when the passphrase changes", func() {
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			_, err := conf.PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+
+			env[EnvPassphrase] = "wrong-" + testPassphrase
+			_, err = conf.PrivateRSAKey()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("parses the key again when the source changes", func() {
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			first, err := conf.PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+
+			env[EnvKeyContent] = string(testEncryptedPrivateKeyConf)
+			second, err := conf.PrivateRSAKey()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second).ToNot(BeIdenticalTo(first))
+			Expect(second.Equal(first)).To(BeTrue())
+		})
+	})
+
 	Context("invalid configuration", func() {
 		Context("invalid private key", func() {
 			BeforeEach(func() {
@@ -190,6 +303,50 @@ var _ = Describe("OciCliEnvironmentConfigurationProvider", func() {
 			})
 		})
 
+		isValid := func(conf common.ConfigurationProvider) error {
+			_, err := common.IsConfigurationProviderValid(conf)
+			return err
+		}
+		keyID := func(conf common.ConfigurationProvider) error {
+			_, err := conf.KeyID()
+			return err
+		}
+
+		DescribeTable("malformed OCIDs",
+			func(get func(common.ConfigurationProvider) error, envVar, value, resourceType string) {
+				_ = os.Setenv(EnvTenancy, testTenancy)
+				_ = os.Setenv(EnvUser, testUser)
+				_ = os.Setenv(EnvFingerprint, testFingerprint)
+				_ = os.Setenv(envVar, value)
+
+				err := get(conf)
+				var ocidErr *InvalidOCIDError
+				Expect(errors.As(err, &ocidErr)).To(BeTrue())
+				Expect(ocidErr.EnvVar).To(Equal(envVar))
+				Expect(ocidErr.ResourceType).To(Equal(resourceType))
+			},
+			Entry("tenancy with whitespace", isValid, EnvTenancy, testTenancy+"\n", "tenancy"),
+			Entry("quoted tenancy", isValid, EnvTenancy, `"`+testTenancy+`"`, "tenancy"),
+			Entry("compartment as tenancy", isValid, EnvTenancy, "ocid1.compartment.oc1..aaaaaaaatestcompartment", "tenancy"),
+			Entry("user that is not an OCID", isValid, EnvUser, "test-user", "user"),
+			Entry("tenancy as user", isValid, EnvUser, testTenancy, "user"),
+			Entry("user that is not an OCID in the KeyID", keyID, EnvUser, "test-user", "user"),
+		)
+
+		It("does not sign with the security token when the user is malformed", func() {
+			_ = os.Setenv(EnvTenancy, testTenancy)
+			_ = os.Setenv(EnvUser, "test-user")
+			_ = os.Setenv(EnvFingerprint, testFingerprint)
+			_ = os.Setenv(EnvAuth, string(SecurityTokenType))
+			tokenFilePath := createTempFile([]byte(testSecurityToken))
+			DeferCleanup(os.Remove, tokenFilePath)
+			_ = os.Setenv(EnvSecurityTokenFile, tokenFilePath)
+
+			_, err := conf.KeyID()
+			var ocidErr *InvalidOCIDError
+			Expect(errors.As(err, &ocidErr)).To(BeTrue())
+		})
+
 		Context("invalid fingerprint", func() {
 			BeforeEach(func() {
 				_ = os.Setenv(EnvUser, testUser)
@@ -236,6 +393,19 @@ var _ = Describe("OciCliEnvironmentConfigurationProvider", func() {
 			})
 		})
 
+		DescribeTable("translates principal auth types",
+			func(authType, expected common.AuthenticationType) {
+				_ = os.Setenv(EnvAuth, string(authType))
+				at, err := conf.AuthType()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(at.AuthType).To(Equal(expected))
+			},
+			Entry("instance principal", InstancePrincipalType, common.InstancePrincipal),
+			Entry("instance obo user", InstanceOboUserType, common.InstancePrincipalDelegationToken),
+			Entry("resource principal", ResourcePrincipalType, ResourcePrincipalType),
+			Entry("oke workload identity", OkeWorkloadIdentityType, OkeWorkloadIdentityType),
+		)
+
 		Context("no user and invalid auth type", func() {
 			BeforeEach(func() {
 				_ = os.Setenv(EnvTenancy, testTenancy)
@@ -288,6 +458,34 @@ var _ = Describe("OciCliEnvironmentConfigurationProvider", func() {
 
 })
 
+func BenchmarkPrivateRSAKey(b *testing.B) {
+	keyPath := createTempFile(testEncryptedPrivateKeyConf)
+	defer func() { _ = os.Remove(keyPath) }()
+	conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{EnvKeyFile: keyPath, EnvPassphrase: testPassphrase}))
+
+	for b.Loop() {
+		if _, err := conf.PrivateRSAKey(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPrivateRSAKeyUncached reads and parses the key the way PrivateRSAKey did before it was cached
+func BenchmarkPrivateRSAKeyUncached(b *testing.B) {
+	keyPath := createTempFile(testEncryptedPrivateKeyConf)
+	defer func() { _ = os.Remove(keyPath) }()
+
+	for b.Loop() {
+		content, err := os.ReadFile(keyPath)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err = common.PrivateKeyFromBytesWithPassword(content, []byte(testPassphrase)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func createTempFile(content []byte) string {
 	f, _ := os.CreateTemp("", "ociclienvprovider")
 	defer func() { _ = f.Close() }()
@@ -296,10 +494,11 @@ func createTempFile(content []byte) string {
 }
 
 var (
-	testUser          = "test-user"
+	testUser          = "ocid1.user.oc1..aaaaaaaatestuser"
 	testFingerprint   = "test-fingerprint"
-	testTenancy       = "test-tenancy"
-	testRegion        = "test-region"
+	testTenancy       = "ocid1.tenancy.oc1..aaaaaaaatesttenancy"
+	testAltTenancy    = "ocid1.tenancy.oc1..aaaaaaaaalttenancy"
+	testRegion        = "us-ashburn-1"
 	testSecurityToken = "test-security-token"
 
 	testPk, _          = rsa.GenerateKey(rand.Reader, 4096)
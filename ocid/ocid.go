@@ -0,0 +1,137 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ocid parses and validates Oracle Cloud Identifiers of the form
+// ocid1.<resource type>.<realm>.[region][.future use].<unique id>
+package ocid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const versionPrefix = "ocid"
+
+var (
+	ErrInvalid           = errors.New("invalid OCID")
+	ErrWrongResourceType = errors.New("wrong OCID resource type")
+)
+
+// OCID is a parsed Oracle Cloud Identifier
+type OCID struct {
+	Version      string
+	ResourceType string
+	Realm        string
+	// Region is empty for resources that are not regional, such as tenancies and users
+	Region string
+	// FutureUse is the optional segment between the region and the unique id
+	FutureUse string
+	UniqueID  string
+}
+
+// String gives back the OCID in its canonical form
+func (o OCID) String() string {
+	parts := []string{o.Version, o.ResourceType, o.Realm, o.Region}
+	if o.FutureUse != "" {
+		parts = append(parts, o.FutureUse)
+	}
+	return strings.Join(append(parts, o.UniqueID), ".")
+}
+
+// Parse parses s as an OCID
+func Parse(s string) (OCID, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 5 && len(parts) != 6 {
+		return OCID{}, invalid(s, "expected 5 or 6 dot separated segments, got %d", len(parts))
+	}
+
+	o := OCID{
+		Version:      parts[0],
+		ResourceType: parts[1],
+		Realm:        parts[2],
+		Region:       parts[3],
+		UniqueID:     parts[len(parts)-1],
+	}
+	if len(parts) == 6 {
+		o.FutureUse = parts[4]
+	}
+
+	version, found := strings.CutPrefix(o.Version, versionPrefix)
+	if !found || version == "" || !isValid(version, isDigit) {
+		return OCID{}, invalid(s, "unknown version %q", o.Version)
+	}
+	for _, segment := range []struct {
+		name     string
+		value    string
+		optional bool
+		valid    func(rune) bool
+	}{
+		{"resource type", o.ResourceType, false, isLowerAlnum},
+		{"realm", o.Realm, false, isLowerAlnum},
+		{"region", o.Region, true, isRegionChar},
+		{"future use", o.FutureUse, true, isLowerAlnum},
+		{"unique id", o.UniqueID, false, isLowerAlnum},
+	} {
+		if segment.value == "" {
+			if segment.optional {
+				continue
+			}
+			return OCID{}, invalid(s, "missing %s", segment.name)
+		}
+		if !isValid(segment.value, segment.valid) {
+			return OCID{}, invalid(s, "invalid %s %q", segment.name, segment.value)
+		}
+	}
+	return o, nil
+}
+
+// ParseType parses s as an OCID of the given resource type, such as "tenancy" or "user"
+func ParseType(s, resourceType string) (OCID, error) {
+	o, err := Parse(s)
+	if err != nil {
+		return OCID{}, err
+	}
+	if o.ResourceType != resourceType {
+		return OCID{}, fmt.Errorf("%w: expected %s, got %s", ErrWrongResourceType, resourceType, o.ResourceType)
+	}
+	return o, nil
+}
+
+func invalid(s, format string, args ...any) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalid, s, fmt.Sprintf(format, args...))
+}
+
+func isValid(s string, valid func(rune) bool) bool {
+	for _, r := range s {
+		if !valid(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isLowerAlnum(r rune) bool {
+	return isDigit(r) || (r >= 'a' && r <= 'z')
+}
+
+func isRegionChar(r rune) bool {
+	return isLowerAlnum(r) || r == '-'
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocid_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ontariosystems/oci-cli-env-provider/ocid"
+)
+
+func TestOCID(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OCID Suite")
+}
+
+var _ = Describe("Parse", func() {
+	It("parses a global OCID", func() {
+		o, err := ocid.Parse("ocid1.tenancy.oc1..aaaaaaaaba3pv6wkcr4jqae5f15p2b2m2yt2j6rx32uzr4h25vqstifsfdsq")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o).To(Equal(ocid.OCID{
+			Version:      "ocid1",
+			ResourceType: "tenancy",
+			Realm:        "oc1",
+			UniqueID:     "aaaaaaaaba3pv6wkcr4jqae5f15p2b2m2yt2j6rx32uzr4h25vqstifsfdsq",
+		}))
+	})
+
+	It("parses a regional OCID", func() {
+		o, err := ocid.Parse("ocid1.instance.oc1.us-ashburn-1.anuwcljt3example")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o.ResourceType).To(Equal("instance"))
+		Expect(o.Region).To(Equal("us-ashburn-1"))
+		Expect(o.UniqueID).To(Equal("anuwcljt3example"))
+	})
+
+	It("parses the future use segment", func() {
+		o, err := ocid.Parse("ocid1.volume.oc1.phx.abc.uniqueid")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o.Region).To(Equal("phx"))
+		Expect(o.FutureUse).To(Equal("abc"))
+		Expect(o.UniqueID).To(Equal("uniqueid"))
+	})
+
+	DescribeTable("round trips",
+		func(s string) {
+			o, err := ocid.Parse(s)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(o.String()).To(Equal(s))
+		},
+		Entry("global", "ocid1.user.oc1..aaaaaaaauser"),
+		Entry("regional", "ocid1.instance.oc1.phx.aaaaaaaainstance"),
+		Entry("future use", "ocid1.volume.oc1.phx.abc.aaaaaaaavolume"),
+	)
+
+	DescribeTable("rejects malformed OCIDs",
+		func(s, reason string) {
+			_, err := ocid.Parse(s)
+			Expect(err).To(MatchError(ocid.ErrInvalid))
+			Expect(err).To(MatchError(ContainSubstring(reason)))
+		},
+		Entry("empty", "", "segments"),
+		Entry("not an OCID", "test-tenancy", "segments"),
+		Entry("too many segments", "ocid1.tenancy.oc1.phx.a.b.c", "segments"),
+		Entry("unknown version", "ocidx.tenancy.oc1..aaaa", "version"),
+		Entry("missing resource type", "ocid1..oc1..aaaa", "resource type"),
+		Entry("missing realm", "ocid1.tenancy...aaaa", "realm"),
+		Entry("missing unique id", "ocid1.tenancy.oc1..", "unique id"),
+		Entry("trailing whitespace", "ocid1.tenancy.oc1..aaaa\n", "unique id"),
+		Entry("leading whitespace", " ocid1.tenancy.oc1..aaaa", "version"),
+		Entry("quoted", `"ocid1.tenancy.oc1..aaaa"`, "version"),
+		Entry("upper case", "ocid1.TENANCY.oc1..aaaa", "resource type"),
+	)
+})
+
+var _ = Describe("ParseType", func() {
+	It("accepts the expected resource type", func() {
+		o, err := ocid.ParseType("ocid1.user.oc1..aaaaaaaauser", "user")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o.ResourceType).To(Equal("user"))
+	})
+
+	It("rejects another resource type", func() {
+		_, err := ocid.ParseType("ocid1.compartment.oc1..aaaaaaaacompartment", "tenancy")
+		Expect(err).To(MatchError(ocid.ErrWrongResourceType))
+		Expect(err).To(MatchError(ContainSubstring("compartment")))
+	})
+
+	It("rejects malformed OCIDs", func() {
+		_, err := ocid.ParseType("tenancy", "tenancy")
+		Expect(err).To(MatchError(ocid.ErrInvalid))
+	})
+})
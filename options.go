@@ -0,0 +1,188 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"os"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// LookupEnvFunc retrieves the value of the environment variable named by the key.
+// It has the same semantics as [os.LookupEnv].
+type LookupEnvFunc func(key string) (string, bool)
+
+// EnvMap returns a [LookupEnvFunc] that looks up values in env instead of the process environment
+func EnvMap(env map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+// Option configures the providers created by this package
+type Option func(*options)
+
+// DefaultOption configures [NewDefaultConfigProvider], [ClientSettingsFromEnv] and [ConfigureClient]. Every
+// [Option] is also a DefaultOption, while the options that choose the sources of NewDefaultConfigProvider,
+// such as [WithProfile], are only DefaultOptions.
+type DefaultOption interface {
+	applyDefault(o *options)
+}
+
+func (opt Option) applyDefault(o *options) {
+	opt(o)
+}
+
+type defaultOption func(*options)
+
+func (opt defaultOption) applyDefault(o *options) {
+	opt(o)
+}
+
+type options struct {
+	lookupEnv        LookupEnvFunc
+	passphraseSource PassphraseSource
+	filePermissions  FilePermissionPolicy
+
+	verifyFingerprint bool
+	deriveFingerprint bool
+
+	tokenRefresh       SecurityTokenRefreshFunc
+	tokenRefreshMargin time.Duration
+
+	configFilePath    string
+	profileName       string
+	excludeSDKDefault bool
+	strictEnv         bool
+	extraProviders    []positionedProvider
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{lookupEnv: os.LookupEnv}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func newDefaultOptions(opts ...DefaultOption) *options {
+	o := newOptions()
+	for _, opt := range opts {
+		opt.applyDefault(o)
+	}
+	return o
+}
+
+// WithLookupEnv sets the source of the environment variables. The default is [os.LookupEnv].
+func WithLookupEnv(lookupEnv LookupEnvFunc) Option {
+	return func(o *options) {
+		if lookupEnv != nil {
+			o.lookupEnv = lookupEnv
+		}
+	}
+}
+
+// WithEnvMap reads the environment variables from env instead of the process environment
+func WithEnvMap(env map[string]string) Option {
+	return WithLookupEnv(EnvMap(env))
+}
+
+// WithSecurityTokenRefresh registers refresh to be called when the security token expires within
+// margin. The expiry is checked whenever the token is read, and the token file is read again after
+// refresh returns.
+func WithSecurityTokenRefresh(margin time.Duration, refresh SecurityTokenRefreshFunc) Option {
+	return func(o *options) {
+		o.tokenRefresh = refresh
+		o.tokenRefreshMargin = margin
+	}
+}
+
+// WithPassphraseSource sets where the passphrase of the private key comes from, instead of
+// OCI_CLI_PASSPHRASE_FILE or OCI_CLI_PASSPHRASE
+func WithPassphraseSource(source PassphraseSource) Option {
+	return func(o *options) {
+		o.passphraseSource = source
+	}
+}
+
+// WithFilePermissionPolicy sets how private key and security token files that other users can access
+// are handled. The default is [IgnoreInsecureFiles].
+func WithFilePermissionPolicy(policy FilePermissionPolicy) Option {
+	return func(o *options) {
+		o.filePermissions = policy
+	}
+}
+
+// WithFingerprintVerification makes KeyFingerprint return a [FingerprintMismatchError] when
+// OCI_CLI_FINGERPRINT does not belong to the private key
+func WithFingerprintVerification() Option {
+	return func(o *options) {
+		o.verifyFingerprint = true
+	}
+}
+
+// WithDerivedFingerprint makes KeyFingerprint compute the fingerprint from the private key when
+// OCI_CLI_FINGERPRINT is not set
+func WithDerivedFingerprint() Option {
+	return func(o *options) {
+		o.deriveFingerprint = true
+	}
+}
+
+// WithConfigFile sets the oci cli config file of [NewDefaultConfigProvider] instead of
+// OCI_CLI_CONFIG_FILE or ~/.oci/config
+func WithConfigFile(configFilePath string) DefaultOption {
+	return defaultOption(func(o *options) {
+		o.configFilePath = configFilePath
+	})
+}
+
+// WithProfile sets the config file profile of [NewDefaultConfigProvider] instead of OCI_CLI_PROFILE
+// or the default_profile of the rc or config file
+func WithProfile(profileName string) DefaultOption {
+	return defaultOption(func(o *options) {
+		o.profileName = profileName
+	})
+}
+
+// WithSDKDefault sets whether [NewDefaultConfigProvider] falls back to [common.DefaultConfigProvider].
+// The default is to include it.
+func WithSDKDefault(include bool) DefaultOption {
+	return defaultOption(func(o *options) {
+		o.excludeSDKDefault = !include
+	})
+}
+
+// WithProvider adds provider to the sources of [NewDefaultConfigProvider] at position. Providers added
+// at the same position keep the order of the options.
+func WithProvider(position ProviderPosition, provider common.ConfigurationProvider) DefaultOption {
+	return defaultOption(func(o *options) {
+		o.extraProviders = append(o.extraProviders, positionedProvider{position, provider})
+	})
+}
+
+// WithStrictEnv makes [NewDefaultConfigProvider] use only the oci cli environment variables as soon as any
+// of OCI_CLI_TENANCY, OCI_CLI_USER, OCI_CLI_FINGERPRINT, OCI_CLI_KEY_CONTENT, OCI_CLI_KEY_FILE or
+// OCI_CLI_SECURITY_TOKEN_FILE is set. Until the rest of the variables the credentials need are set, every
+// method returns an [EnvError] for each of them, joined with [errors.Join].
+func WithStrictEnv() DefaultOption {
+	return defaultOption(func(o *options) {
+		o.strictEnv = true
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+)
+
+// PassphraseSource returns the passphrase of the private key
+type PassphraseSource func() (string, error)
+
+// PassphraseFromFile returns a [PassphraseSource] that reads the passphrase from a file, such as a
+// mounted secret. Trailing line breaks are removed.
+func PassphraseFromFile(filePath string) PassphraseSource {
+	var file internal.CachedFile
+	return func() (string, error) {
+		return readPassphraseFile(&file, filePath)
+	}
+}
+
+// PassphraseFromCommand returns a [PassphraseSource] that runs a command and uses its output as the
+// passphrase. The command is only run again if it failed. Trailing line breaks are removed.
+func PassphraseFromCommand(name string, args ...string) PassphraseSource {
+	var (
+		mu         sync.Mutex
+		passphrase *string
+	)
+	return func() (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if passphrase != nil {
+			return *passphrase, nil
+		}
+
+		var stdout bytes.Buffer
+		cmd := exec.Command(name, args...)
+		cmd.Stdout = &stdout
+		if err := cmd.Run(); err != nil {
+			return "", err
+		}
+
+		value := strings.TrimRight(stdout.String(), "\r\n")
+		passphrase = &value
+		return value, nil
+	}
+}
+
+func readPassphraseFile(file *internal.CachedFile, filePath string) (string, error) {
+	content, err := file.Read(internal.ExpandPath(filePath))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
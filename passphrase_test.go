@@ -0,0 +1,125 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+)
+
+var _ = Describe("Passphrase sources", func() {
+	var env map[string]string
+
+	BeforeEach(func() {
+		privateKeyPath := createTempFile(testEncryptedPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+		env = map[string]string{EnvKeyFile: privateKeyPath}
+	})
+
+	It("reads the passphrase file environment variable", func() {
+		passphraseFile := createTempFile([]byte(testPassphrase + "\n"))
+		DeferCleanup(os.Remove, passphraseFile)
+		env[EnvPassphraseFile] = passphraseFile
+		env[EnvPassphrase] = "wrong-" + testPassphrase
+
+		key, err := OciCliEnvironmentConfigurationProvider(WithEnvMap(env)).PrivateRSAKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.Equal(testPk)).To(BeTrue())
+	})
+
+	It("returns the error of the passphrase file", func() {
+		env[EnvPassphraseFile] = "/does/not/exist"
+		_, err := OciCliEnvironmentConfigurationProvider(WithEnvMap(env)).PrivateRSAKey()
+		Expect(err).To(MatchError(ContainSubstring("/does/not/exist")))
+	})
+
+	It("reports the missing key before reading the passphrase", func() {
+		calls := 0
+		env = map[string]string{EnvPassphraseFile: "/does/not/exist"}
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+		_, err := conf.PrivateRSAKey()
+		Expect(err).To(MatchError(ContainSubstring(EnvKeyContent + " is not set")))
+		Expect(err).To(MatchError(ContainSubstring(EnvKeyFile + " is not set")))
+
+		conf = OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithPassphraseSource(func() (string, error) {
+			calls++
+			return testPassphrase, nil
+		}))
+		_, err = conf.PrivateRSAKey()
+		Expect(err).To(MatchError(ContainSubstring(EnvKeyFile + " is not set")))
+		Expect(calls).To(BeZero())
+	})
+
+	It("reads the passphrase from a file", func() {
+		passphraseFile := createTempFile([]byte(testPassphrase))
+		DeferCleanup(os.Remove, passphraseFile)
+
+		Expect(PassphraseFromFile(passphraseFile)()).To(Equal(testPassphrase))
+	})
+
+	It("reads the passphrase from a command", func() {
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithPassphraseSource(PassphraseFromCommand("echo", testPassphrase)))
+		_, err := conf.PrivateRSAKey()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns the error of the command", func() {
+		_, err := PassphraseFromCommand("false")()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reads the passphrase from a callback", func() {
+		calls := 0
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithPassphraseSource(func() (string, error) {
+			calls++
+			return testPassphrase, nil
+		}))
+
+		_, err := conf.PrivateRSAKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("returns the error of the callback", func() {
+		sourceErr := errors.New("vault unavailable")
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithPassphraseSource(func() (string, error) {
+			return "", sourceErr
+		}))
+
+		_, err := conf.PrivateRSAKey()
+		Expect(err).To(MatchError(sourceErr))
+	})
+
+	It("uses the passphrase file for the config file profile", func() {
+		passphraseFile := createTempFile([]byte(testPassphrase))
+		DeferCleanup(os.Remove, passphraseFile)
+		configFile := createTempFile([]byte("[DEFAULT]\n[test]\nkey_file = " + env[EnvKeyFile] + "\n"))
+		DeferCleanup(os.Remove, configFile)
+
+		_ = os.Setenv(EnvConfigFile, configFile)
+		_ = os.Setenv(EnvProfile, "test")
+		_ = os.Setenv(EnvPassphraseFile, passphraseFile)
+
+		key, err := DefaultConfigProvider().PrivateRSAKey()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.Equal(testPk)).To(BeTrue())
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+)
+
+// DelegationTokenProvider is implemented by providers that can supply a delegation token
+type DelegationTokenProvider interface {
+	DelegationToken() (string, error)
+}
+
+// DelegationTokenConfigProvider returns a [LazyConfigProvider] that uses instance principal credentials
+// on behalf of the user of the delegation token in OCI_CLI_DELEGATION_TOKEN_FILE, the same as the
+// oci cli does in Cloud Shell or with OCI_CLI_AUTH=instance_obo_user.
+func DelegationTokenConfigProvider(opts ...Option) common.ConfigurationProvider {
+	envProvider := newOciCliEnvProvider(newOptions(opts...))
+	return LazyConfigProvider(func() (common.ConfigurationProvider, error) {
+		token, err := envProvider.DelegationToken()
+		if err != nil {
+			return nil, err
+		}
+		return auth.InstancePrincipalDelegationTokenConfigurationProvider(&token)
+	})
+}
+
+// principalConfigProvider returns a [LazyConfigProvider] for the principal based OCI_CLI_AUTH, or nil if
+// it is not principal based. The delegation token for [InstanceOboUserType] is read from
+// OCI_CLI_DELEGATION_TOKEN_FILE, or else the delegation_token_file of the profile in the config file.
+// OCI_CLI_REGION overrides the region of the principal, the same as it does for the oci cli.
+func principalConfigProvider(envProvider *ociCliEnvProvider, configFilePath, profileName string) common.ConfigurationProvider {
+	authType := common.AuthenticationType(envProvider.getEnv(EnvAuth))
+
+	_, hasRegion := envProvider.lookupEnv(EnvRegion)
+	region, regionErr := envProvider.Region()
+
+	provider := newPrincipalConfigProvider(authType, envProvider, configFilePath, profileName, common.Region(region))
+	if provider == nil {
+		return nil
+	}
+
+	provider = describedProvider{provider, fmt.Sprintf("%s authentication from %s", authType, envSource(EnvAuth))}
+	if hasRegion {
+		return principalRegionProvider{ConfigurationProvider: provider, region: region, err: regionErr}
+	}
+	return provider
+}
+
+// newPrincipalConfigProvider returns the provider for authType, in region unless it is empty
+func newPrincipalConfigProvider(authType common.AuthenticationType, envProvider *ociCliEnvProvider, configFilePath, profileName string, region common.Region) common.ConfigurationProvider {
+	switch authType {
+	case InstancePrincipalType:
+		return LazyConfigProvider(func() (common.ConfigurationProvider, error) {
+			if region != "" {
+				return auth.InstancePrincipalConfigurationProviderForRegion(region)
+			}
+			return auth.InstancePrincipalConfigurationProvider()
+		})
+	case ResourcePrincipalType:
+		return LazyConfigProvider(func() (common.ConfigurationProvider, error) {
+			if region != "" {
+				return auth.ResourcePrincipalConfigurationProviderForRegion(region)
+			}
+			return auth.ResourcePrincipalConfigurationProvider()
+		})
+	case OkeWorkloadIdentityType:
+		// the oci-go-sdk has no region variant, the region is overridden by principalRegionProvider instead
+		return LazyConfigProvider(func() (common.ConfigurationProvider, error) {
+			return auth.OkeWorkloadIdentityConfigurationProvider()
+		})
+	case InstanceOboUserType:
+		return LazyConfigProvider(func() (common.ConfigurationProvider, error) {
+			var token string
+			var err error
+			if _, ok := envProvider.lookupEnv(EnvDelegationTokenFile); ok {
+				token, err = envProvider.DelegationToken()
+			} else {
+				token, err = readDelegationTokenFromProfile(configFilePath, profileName)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if region != "" {
+				return auth.InstancePrincipalDelegationTokenConfigurationProviderForRegion(&token, region)
+			}
+			return auth.InstancePrincipalDelegationTokenConfigurationProvider(&token)
+		})
+	}
+	return nil
+}
+
+// principalRegionProvider answers the region of a principal provider from OCI_CLI_REGION, without
+// initializing the principal provider
+type principalRegionProvider struct {
+	common.ConfigurationProvider
+	region string
+	err    error
+}
+
+func (p principalRegionProvider) Region() (string, error) {
+	return p.region, p.err
+}
+
+// DescribeSource names OCI_CLI_REGION for the region, and the principal provider for the other fields
+func (p principalRegionProvider) DescribeSource(field Field) string {
+	if field == FieldRegion {
+		return envSource(EnvRegion)
+	}
+	return describeSource(p.ConfigurationProvider, field)
+}
+
+// Warm warms the principal provider
+func (p principalRegionProvider) Warm(ctx context.Context) error {
+	return Warm(ctx, p.ConfigurationProvider)
+}
+
+func readDelegationTokenFromProfile(configFilePath, profileName string) (string, error) {
+	profile, err := LoadConfigProfile(configFilePath, profileName)
+	if err != nil {
+		return "", err
+	}
+
+	tokenPath, _ := profile.Get("delegation_token_file")
+	if tokenPath == "" {
+		return "", fmt.Errorf("%s requires %s or delegation_token_file in profile %s of %s", InstanceOboUserType, EnvDelegationTokenFile, profile.Name, configFilePath)
+	}
+	return readDelegationToken(tokenPath)
+}
+
+func readDelegationToken(tokenPath string) (string, error) {
+	token, err := os.ReadFile(internal.ExpandPath(tokenPath))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(token)), nil
+}
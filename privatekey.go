@@ -0,0 +1,85 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"sync"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// privateKeyCache keeps the last parsed private key, so an unchanged key is not parsed and
+// decrypted again for every signed request
+type privateKeyCache struct {
+	mu  sync.Mutex
+	id  [sha256.Size]byte
+	key *rsa.PrivateKey
+}
+
+// parse returns the cached key if source, content and passphrase are the same as the last call
+func (c *privateKeyCache) parse(source string, content []byte, passphrase string) (*rsa.PrivateKey, error) {
+	h := sha256.New()
+	writeHashPart(h, []byte(source))
+	writeHashPart(h, content)
+	writeHashPart(h, []byte(passphrase))
+
+	var id [sha256.Size]byte
+	h.Sum(id[:0])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != nil && c.id == id {
+		return c.key, nil
+	}
+
+	key, err := common.PrivateKeyFromBytesWithPassword(content, []byte(passphrase))
+	if err != nil {
+		return nil, err
+	}
+	c.id, c.key = id, key
+	return key, nil
+}
+
+func writeHashPart(h hash.Hash, part []byte) {
+	_ = binary.Write(h, binary.BigEndian, uint64(len(part)))
+	_, _ = h.Write(part)
+}
+
+// privateKeyFile reads and parses a private key file, only parsing it again when it changes
+type privateKeyFile struct {
+	file  internal.CachedFile
+	cache privateKeyCache
+}
+
+func (f *privateKeyFile) read(keyPath, passphrase string, policy FilePermissionPolicy) (*rsa.PrivateKey, error) {
+	keyPath = internal.ExpandPath(keyPath)
+	if err := policy.checkFilePermissions(keyPath); err != nil {
+		return nil, err
+	}
+
+	content, err := f.file.Read(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return f.cache.parse(keyPath, content, passphrase)
+}
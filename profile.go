@@ -0,0 +1,107 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"gopkg.in/ini.v1"
+)
+
+// parentProfileKey names the profile a profile of the oci cli config file inherits from
+const parentProfileKey = "parent_profile"
+
+// ConfigProfile is a profile of the oci cli config file together with the keys it inherits.
+//
+// A key that is not set in the profile is taken from the profile named by its parent_profile key, and so on
+// up the chain, and lastly from the DEFAULT section.
+type ConfigProfile struct {
+	// Name of the profile
+	Name string
+	// FilePath of the config file
+	FilePath string
+	// Chain lists the profiles that are searched for a key, starting with the profile itself
+	Chain []string
+
+	values map[string]profileValue
+}
+
+type profileValue struct {
+	value   string
+	profile string
+}
+
+// LoadConfigProfile loads the profile from the oci cli config file. The DEFAULT section is loaded when
+// profileName is empty.
+func LoadConfigProfile(filePath, profileName string) (*ConfigProfile, error) {
+	cliConf, err := ini.Load(internal.ExpandPath(filePath))
+	if err != nil {
+		return nil, err
+	}
+	return loadConfigProfile(cliConf, filePath, profileName)
+}
+
+func loadConfigProfile(cliConf *ini.File, filePath, profileName string) (*ConfigProfile, error) {
+	if profileName == "" {
+		profileName = ini.DefaultSection
+	}
+
+	var chain []string
+	for name := profileName; name != ""; {
+		if i := slices.Index(chain, name); i >= 0 {
+			return nil, &ProfileCycleError{FilePath: filePath, Profiles: append(chain[i:], name)}
+		}
+		section, err := cliConf.GetSection(name)
+		if err != nil {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("%w: %s in %s", ErrProfileNotFound, name, filePath)
+			}
+			return nil, fmt.Errorf("%w: %s, the parent of %s in %s", ErrProfileNotFound, name, chain[len(chain)-1], filePath)
+		}
+		chain = append(chain, name)
+		if name == ini.DefaultSection {
+			break
+		}
+		name = section.KeysHash()[parentProfileKey]
+	}
+	if !slices.Contains(chain, ini.DefaultSection) {
+		chain = append(chain, ini.DefaultSection)
+	}
+
+	values := make(map[string]profileValue)
+	for _, name := range slices.Backward(chain) {
+		for key, value := range cliConf.Section(name).KeysHash() {
+			if key != parentProfileKey {
+				values[key] = profileValue{value: value, profile: name}
+			}
+		}
+	}
+	return &ConfigProfile{Name: profileName, FilePath: filePath, Chain: chain, values: values}, nil
+}
+
+// Get gives back the value of key, which may be inherited
+func (p *ConfigProfile) Get(key string) (string, bool) {
+	v, ok := p.values[key]
+	return v.value, ok
+}
+
+// Source gives back the name of the profile key is set in, or "" if it is not set
+func (p *ConfigProfile) Source(key string) string {
+	return p.values[key].profile
+}
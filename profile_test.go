@@ -0,0 +1,147 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+)
+
+var _ = Describe("LoadConfigProfile", func() {
+	const testProfileConfig = `[DEFAULT]
+region = us-ashburn-1
+tenancy = ocid1.tenancy.oc1..aaaaaaaadefault
+
+[team]
+tenancy = ocid1.tenancy.oc1..aaaaaaaateam
+key_file = ~/.oci/team.pem
+
+[alice]
+parent_profile = team
+user = ocid1.user.oc1..aaaaaaaaalice
+fingerprint = aa:bb
+
+[alice-phx]
+parent_profile = alice
+region = us-phoenix-1
+
+[orphan]
+parent_profile = missing
+
+[loop-a]
+parent_profile = loop-b
+
+[loop-b]
+parent_profile = loop-c
+
+[loop-c]
+parent_profile = loop-b
+`
+	var configFilePath string
+
+	get := func(profile *ConfigProfile, key string) string {
+		value, ok := profile.Get(key)
+		Expect(ok).To(BeTrue(), key)
+		return value
+	}
+
+	BeforeEach(func() {
+		configFilePath = createTempFile([]byte(testProfileConfig))
+		DeferCleanup(os.Remove, configFilePath)
+	})
+
+	It("falls back to DEFAULT", func() {
+		profile, err := LoadConfigProfile(configFilePath, "team")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profile.Chain).To(Equal([]string{"team", "DEFAULT"}))
+		Expect(get(profile, "region")).To(Equal("us-ashburn-1"))
+		Expect(profile.Source("region")).To(Equal("DEFAULT"))
+		Expect(get(profile, "tenancy")).To(Equal("ocid1.tenancy.oc1..aaaaaaaateam"))
+		Expect(profile.Source("tenancy")).To(Equal("team"))
+	})
+
+	It("inherits through several parents", func() {
+		profile, err := LoadConfigProfile(configFilePath, "alice-phx")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profile.Name).To(Equal("alice-phx"))
+		Expect(profile.FilePath).To(Equal(configFilePath))
+		Expect(profile.Chain).To(Equal([]string{"alice-phx", "alice", "team", "DEFAULT"}))
+
+		Expect(get(profile, "region")).To(Equal("us-phoenix-1"))
+		Expect(get(profile, "user")).To(Equal("ocid1.user.oc1..aaaaaaaaalice"))
+		Expect(profile.Source("user")).To(Equal("alice"))
+		Expect(get(profile, "key_file")).To(Equal("~/.oci/team.pem"))
+		Expect(profile.Source("key_file")).To(Equal("team"))
+		Expect(get(profile, "tenancy")).To(Equal("ocid1.tenancy.oc1..aaaaaaaateam"))
+	})
+
+	It("does not expose the parent key", func() {
+		profile, err := LoadConfigProfile(configFilePath, "alice")
+		Expect(err).ToNot(HaveOccurred())
+		_, ok := profile.Get("parent_profile")
+		Expect(ok).To(BeFalse())
+		Expect(profile.Source("parent_profile")).To(BeEmpty())
+	})
+
+	It("loads DEFAULT when no profile is given", func() {
+		profile, err := LoadConfigProfile(configFilePath, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profile.Name).To(Equal("DEFAULT"))
+		Expect(profile.Chain).To(Equal([]string{"DEFAULT"}))
+		Expect(get(profile, "region")).To(Equal("us-ashburn-1"))
+	})
+
+	It("reports a missing profile", func() {
+		_, err := LoadConfigProfile(configFilePath, "bob")
+		Expect(err).To(MatchError(ErrProfileNotFound))
+		Expect(err).To(MatchError(ContainSubstring("bob")))
+	})
+
+	It("reports a missing parent", func() {
+		_, err := LoadConfigProfile(configFilePath, "orphan")
+		Expect(err).To(MatchError(ErrProfileNotFound))
+		Expect(err).To(MatchError(ContainSubstring("missing, the parent of orphan")))
+	})
+
+	It("detects cycles", func() {
+		_, err := LoadConfigProfile(configFilePath, "loop-a")
+		var cycleErr *ProfileCycleError
+		Expect(errors.As(err, &cycleErr)).To(BeTrue())
+		Expect(cycleErr.FilePath).To(Equal(configFilePath))
+		Expect(cycleErr.Profiles).To(Equal([]string{"loop-b", "loop-c", "loop-b"}))
+		Expect(err).To(MatchError(ContainSubstring("loop-b -> loop-c -> loop-b")))
+	})
+
+	It("detects a profile that is its own parent", func() {
+		selfPath := createTempFile([]byte("[self]\nparent_profile = self\n"))
+		DeferCleanup(os.Remove, selfPath)
+
+		_, err := LoadConfigProfile(selfPath, "self")
+		var cycleErr *ProfileCycleError
+		Expect(errors.As(err, &cycleErr)).To(BeTrue())
+		Expect(cycleErr.Profiles).To(Equal([]string{"self", "self"}))
+	})
+
+	It("reports a missing config file", func() {
+		_, err := LoadConfigProfile(configFilePath+".missing", "team")
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
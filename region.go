@@ -0,0 +1,93 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+const regionsConfigFilePath = "~/.oci/regions-config.json"
+
+// regionMetadata is the schema of OCI_REGION_METADATA and of the entries in ~/.oci/regions-config.json
+type regionMetadata struct {
+	RealmKey             string `json:"realmKey"`
+	RealmDomainComponent string `json:"realmDomainComponent"`
+	RegionKey            string `json:"regionKey"`
+	RegionIdentifier     string `json:"regionIdentifier"`
+}
+
+func (m regionMetadata) matches(value string) bool {
+	if m.RealmKey == "" || m.RealmDomainComponent == "" || m.RegionKey == "" || m.RegionIdentifier == "" {
+		return false
+	}
+	return strings.EqualFold(m.RegionKey, value) || strings.EqualFold(m.RegionIdentifier, value)
+}
+
+// resolveRegion gives back the region identifier of value, which may be a region identifier or a short code,
+// read from envVar or "" when it is not read from the environment.
+// Regions known to the oci-go-sdk are checked first, then OCI_REGION_METADATA and ~/.oci/regions-config.json.
+func (o *options) resolveRegion(envVar, value string) (string, error) {
+	if value == "" || strings.ContainsFunc(value, func(r rune) bool { return r <= ' ' }) {
+		return "", &UnknownRegionError{EnvVar: envVar, Region: value}
+	}
+
+	// common.StringToRegion is not used, as it reads OCI_REGION_METADATA from the process environment
+	region, ok := regionShortCodes[strings.ToLower(value)]
+	if !ok {
+		region = common.Region(strings.ToLower(value))
+	}
+	if _, err := region.RealmID(); err == nil {
+		return string(region), nil
+	}
+
+	if metadata, ok := o.lookupEnv(EnvRegionMetadata); ok {
+		var m regionMetadata
+		if err := json.Unmarshal([]byte(metadata), &m); err == nil && m.matches(value) {
+			return strings.ToLower(m.RegionIdentifier), nil
+		}
+	}
+
+	regions, err := readRegionsConfig(internal.ExpandPath(regionsConfigFilePath))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", &UnknownRegionError{EnvVar: envVar, Region: value, Err: err}
+	}
+	for _, m := range regions {
+		if m.matches(value) {
+			return strings.ToLower(m.RegionIdentifier), nil
+		}
+	}
+	return "", &UnknownRegionError{EnvVar: envVar, Region: value}
+}
+
+func readRegionsConfig(path string) ([]regionMetadata, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var regions []regionMetadata
+	if err := json.Unmarshal(content, &regions); err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+var _ = Describe("Region", func() {
+	const testRegionMetadata = `{"realmKey":"oc0","realmDomainComponent":"testrealm.com","regionKey":"TST","regionIdentifier":"us-testregion-1"}`
+
+	region := func(env map[string]string) (string, error) {
+		return OciCliEnvironmentConfigurationProvider(WithEnvMap(env)).Region()
+	}
+
+	BeforeEach(func() {
+		home := createTempDir()
+		DeferCleanup(os.RemoveAll, home)
+		DeferCleanup(os.Setenv, "HOME", os.Getenv("HOME"))
+		_ = os.Setenv("HOME", home)
+	})
+
+	DescribeTable("resolves regions known to the sdk",
+		func(value, expected string) {
+			Expect(region(map[string]string{EnvRegion: value})).To(Equal(expected))
+		},
+		Entry("region identifier", "us-ashburn-1", "us-ashburn-1"),
+		Entry("short code", "iad", "us-ashburn-1"),
+		Entry("upper case short code", "PHX", "us-phoenix-1"),
+	)
+
+	It("resolves every short code the same as the sdk", func() {
+		codes := shortCodes("regionshortcodes.go")
+		Expect(codes).ToNot(BeEmpty())
+		for _, code := range codes {
+			expected := common.StringToRegion(code)
+			Expect(expected).ToNot(Equal(common.Region(code)), "the sdk does not know the short code %s", code)
+			Expect(region(map[string]string{EnvRegion: code})).To(Equal(string(expected)), "short code %s", code)
+		}
+	})
+
+	DescribeTable("rejects unknown regions",
+		func(value string) {
+			_, err := region(map[string]string{EnvRegion: value})
+			var regionErr *UnknownRegionError
+			Expect(errors.As(err, &regionErr)).To(BeTrue())
+			Expect(regionErr.EnvVar).To(Equal(EnvRegion))
+			Expect(regionErr.Region).To(Equal(value))
+		},
+		Entry("typo", "us-ashburm-1"),
+		Entry("unknown short code", "xyz"),
+		Entry("empty", ""),
+		Entry("trailing whitespace", "us-ashburn-1\n"),
+	)
+
+	It("resolves regions from OCI_REGION_METADATA", func() {
+		env := map[string]string{EnvRegion: "tst", EnvRegionMetadata: testRegionMetadata}
+		Expect(region(env)).To(Equal("us-testregion-1"))
+
+		env[EnvRegion] = "us-testregion-1"
+		Expect(region(env)).To(Equal("us-testregion-1"))
+	})
+
+	It("does not read OCI_REGION_METADATA from the process environment", func() {
+		DeferCleanup(os.Unsetenv, EnvRegionMetadata)
+		_ = os.Setenv(EnvRegionMetadata, `{"realmKey":"oc0","realmDomainComponent":"testrealm.com","regionKey":"PRC","regionIdentifier":"us-processregion-1"}`)
+
+		_, err := region(map[string]string{EnvRegion: "us-processregion-1"})
+		var regionErr *UnknownRegionError
+		Expect(errors.As(err, &regionErr)).To(BeTrue())
+		_, err = region(map[string]string{EnvRegion: "prc"})
+		Expect(errors.As(err, &regionErr)).To(BeTrue())
+	})
+
+	It("resolves regions from the regions config file", func() {
+		configDir := filepath.Join(os.Getenv("HOME"), ".oci")
+		Expect(os.MkdirAll(configDir, 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(configDir, "regions-config.json"),
+			[]byte(`[{"realmKey":"oc0","realmDomainComponent":"testrealm.com","regionKey":"TCF","regionIdentifier":"us-configregion-1"}]`), 0o600)).To(Succeed())
+
+		Expect(region(map[string]string{EnvRegion: "tcf"})).To(Equal("us-configregion-1"))
+	})
+
+	It("reports an unreadable regions config file", func() {
+		configDir := filepath.Join(os.Getenv("HOME"), ".oci")
+		Expect(os.MkdirAll(configDir, 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(configDir, "regions-config.json"), []byte("not json"), 0o600)).To(Succeed())
+
+		_, err := region(map[string]string{EnvRegion: "us-missing-1"})
+		var regionErr *UnknownRegionError
+		Expect(errors.As(err, &regionErr)).To(BeTrue())
+		Expect(regionErr.Err).To(HaveOccurred())
+	})
+})
+
+// shortCodes returns the keys of the regionShortCodes table in the source file, which is not exported
+func shortCodes(file string) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	Expect(err).ToNot(HaveOccurred())
+
+	var codes []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || spec.Names[0].Name != "regionShortCodes" {
+			return true
+		}
+		for _, elt := range spec.Values[0].(*ast.CompositeLit).Elts {
+			code, err := strconv.Unquote(elt.(*ast.KeyValueExpr).Key.(*ast.BasicLit).Value)
+			Expect(err).ToNot(HaveOccurred())
+			codes = append(codes, code)
+		}
+		return false
+	})
+	return codes
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import "github.com/oracle/oci-go-sdk/v65/common"
+
+// regionShortCodes mirrors the short codes the oci-go-sdk knows as of v65.101.0, which it only exposes
+// through [common.StringToRegion] along with reading the process environment
+var regionShortCodes = map[string]common.Region{
+	"yny": common.RegionAPChuncheon1,
+	"hyd": common.RegionAPHyderabad1,
+	"mel": common.RegionAPMelbourne1,
+	"bom": common.RegionAPMumbai1,
+	"kix": common.RegionAPOsaka1,
+	"icn": common.RegionAPSeoul1,
+	"syd": common.RegionAPSydney1,
+	"nrt": common.RegionAPTokyo1,
+	"yul": common.RegionCAMontreal1,
+	"yyz": common.RegionCAToronto1,
+	"ams": common.RegionEUAmsterdam1,
+	"fra": common.RegionFRA,
+	"zrh": common.RegionEUZurich1,
+	"jed": common.RegionMEJeddah1,
+	"dxb": common.RegionMEDubai1,
+	"gru": common.RegionSASaopaulo1,
+	"cwl": common.RegionUKCardiff1,
+	"lhr": common.RegionLHR,
+	"iad": common.RegionIAD,
+	"phx": common.RegionPHX,
+	"sjc": common.RegionSJC1,
+	"vcp": common.RegionSAVinhedo1,
+	"scl": common.RegionSASantiago1,
+	"mtz": common.RegionILJerusalem1,
+	"mrs": common.RegionEUMarseille1,
+	"sin": common.RegionAPSingapore1,
+	"auh": common.RegionMEAbudhabi1,
+	"lin": common.RegionEUMilan1,
+	"arn": common.RegionEUStockholm1,
+	"jnb": common.RegionAFJohannesburg1,
+	"cdg": common.RegionEUParis1,
+	"qro": common.RegionMXQueretaro1,
+	"mad": common.RegionEUMadrid1,
+	"ord": common.RegionUSChicago1,
+	"mty": common.RegionMXMonterrey1,
+	"aga": common.RegionUSSaltlake2,
+	"bog": common.RegionSABogota1,
+	"vap": common.RegionSAValparaiso1,
+	"xsp": common.RegionAPSingapore2,
+	"ruh": common.RegionMERiyadh1,
+	"onm": common.RegionAPDelhi1,
+	"hsg": common.RegionAPBatam1,
+	"lfi": common.RegionUSLangley1,
+	"luf": common.RegionUSLuke1,
+	"ric": common.RegionUSGovAshburn1,
+	"pia": common.RegionUSGovChicago1,
+	"tus": common.RegionUSGovPhoenix1,
+	"ltn": common.RegionUKGovLondon1,
+	"brs": common.RegionUKGovCardiff1,
+	"nja": common.RegionAPChiyoda1,
+	"ukb": common.RegionAPIbaraki1,
+	"mct": common.RegionMEDccMuscat1,
+	"ibr": common.RegionMEIbri1,
+	"wga": common.RegionAPDccCanberra1,
+	"bgy": common.RegionEUDccMilan1,
+	"mxp": common.RegionEUDccMilan2,
+	"snn": common.RegionEUDccDublin2,
+	"dtm": common.RegionEUDccRating2,
+	"dus": common.RegionEUDccRating1,
+	"ork": common.RegionEUDccDublin1,
+	"dac": common.RegionAPDccGazipur1,
+	"vll": common.RegionEUMadrid2,
+	"str": common.RegionEUFrankfurt2,
+	"beg": common.RegionEUJovanovac1,
+	"doh": common.RegionMEDccDoha1,
+	"ebb": common.RegionUSSomerset1,
+	"ebl": common.RegionUSThames1,
+	"avz": common.RegionEUDccZurich1,
+	"avf": common.RegionEUCrissier1,
+	"ahu": common.RegionMEAbudhabi3,
+	"rba": common.RegionMEAlain1,
+	"rkt": common.RegionMEAbudhabi2,
+	"shj": common.RegionMEAbudhabi4,
+	"dtz": common.RegionAPSeoul2,
+	"dln": common.RegionAPSuwon1,
+	"bno": common.RegionAPChuncheon2,
+	"yxj": common.RegionUSAshburn2,
+	"pgc": common.RegionUSNewark1,
+	"jsk": common.RegionEUBudapest1,
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"gopkg.in/ini.v1"
+)
+
+// optionSource is the source of values set with an [Option]
+const optionSource = "option"
+
+// Resolution describes the config file and profile [NewDefaultConfigProvider] chose, and how
+type Resolution struct {
+	// ConfigFilePath is the path of the config file, with ~ expanded
+	ConfigFilePath string
+	// ConfigFileSource is the environment variable ConfigFilePath is read from, "option" or "default"
+	ConfigFileSource string
+	// RcFilePath is the path of the oci cli rc file, with ~ expanded
+	RcFilePath string
+	// RcFileSource is the environment variable RcFilePath is read from, or "default"
+	RcFileSource string
+	// ProfileName is DEFAULT when no profile is chosen, the same as the oci cli
+	ProfileName string
+	// ProfileSource is the environment variable or file the profile name is read from, "option" or "default"
+	ProfileSource string
+	// Err holds the errors loading the rc file, the config file or the profile
+	Err error
+}
+
+// Resolver is implemented by the providers returned by [NewDefaultConfigProvider]
+type Resolver interface {
+	Resolution() Resolution
+}
+
+// resolveProfile chooses the config file and profile the same way as the oci cli: the profile is read from
+// the options, or else the environment, or else the default_profile of the rc file, or else of the config file
+func resolveProfile(o *options) Resolution {
+	r := Resolution{
+		ConfigFilePath:   internal.ExpandPath(defaultConfigFilePath),
+		ConfigFileSource: "default",
+		RcFilePath:       internal.ExpandPath(defaultRcFilePath),
+		RcFileSource:     "default",
+	}
+	if o.configFilePath != "" {
+		r.ConfigFilePath, r.ConfigFileSource = internal.ExpandPath(o.configFilePath), optionSource
+	} else if value, _ := o.lookupEnv(EnvConfigFile); value != "" {
+		r.ConfigFilePath, r.ConfigFileSource = internal.ExpandPath(value), envSource(EnvConfigFile)
+	}
+	if value, _ := o.lookupEnv(EnvRcFile); value != "" {
+		r.RcFilePath, r.RcFileSource = internal.ExpandPath(value), envSource(EnvRcFile)
+	}
+
+	var errs []error
+	if o.profileName != "" {
+		r.ProfileName, r.ProfileSource = o.profileName, optionSource
+	} else if value, _ := o.lookupEnv(EnvProfile); value != "" {
+		r.ProfileName, r.ProfileSource = value, envSource(EnvProfile)
+	}
+	for _, file := range []struct{ kind, path string }{{"rc file", r.RcFilePath}, {"config file", r.ConfigFilePath}} {
+		if r.ProfileName != "" {
+			break
+		}
+		profileName, err := defaultProfileFromFile(file.path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not load %s %s: %w", file.kind, file.path, err))
+			continue
+		}
+		if profileName != "" {
+			r.ProfileName, r.ProfileSource = profileName, fmt.Sprintf("%s in %s %s", defaultProfileKey, file.kind, file.path)
+		}
+	}
+
+	if r.ProfileName == "" {
+		r.ProfileName, r.ProfileSource = ini.DefaultSection, "default"
+	}
+
+	_, err := os.Stat(r.ConfigFilePath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && r.ConfigFileSource != "default":
+		errs = append(errs, fmt.Errorf("config file %s from %s does not exist", r.ConfigFilePath, r.ConfigFileSource))
+	case errors.Is(err, fs.ErrNotExist) && r.ProfileSource == "default":
+		// neither the config file nor the profile was chosen, so the config file is optional
+	default:
+		if _, err := LoadConfigProfile(r.ConfigFilePath, r.ProfileName); err != nil {
+			errs = append(errs, fmt.Errorf("could not load profile %s from %s: %w", r.ProfileName, r.ProfileSource, err))
+		}
+	}
+	r.Err = errors.Join(errs...)
+	return r
+}
+
+// resolvedProvider adds the [Resolution] to the provider built from it
+type resolvedProvider struct {
+	common.ConfigurationProvider
+	resolution Resolution
+}
+
+func (p resolvedProvider) Resolution() Resolution {
+	return p.resolution
+}
+
+// Explain explains the wrapped provider
+func (p resolvedProvider) Explain() Explanation {
+	return Explain(p.ConfigurationProvider)
+}
+
+// DescribeSource describes the source of the wrapped provider
+func (p resolvedProvider) DescribeSource(field Field) string {
+	return describeSource(p.ConfigurationProvider, field)
+}
+
+// Warm warms the wrapped provider if it implements [Warmer]
+func (p resolvedProvider) Warm(ctx context.Context) error {
+	return Warm(ctx, p.ConfigurationProvider)
+}
+
+// SecurityTokenExpiry returns the expiry of the security token of the wrapped provider
+func (p resolvedProvider) SecurityTokenExpiry() (time.Time, error) {
+	return securityTokenExpiry(p.ConfigurationProvider)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ontariosystems/oci-cli-env-provider/internal"
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// SecurityTokenRefreshFunc refreshes the security token in tokenFile, which expires at expiry.
+// It could e.g. run `oci session refresh`.
+type SecurityTokenRefreshFunc func(tokenFile string, expiry time.Time) error
+
+// SecurityTokenExpirer is implemented by providers that authenticate with a security token
+type SecurityTokenExpirer interface {
+	SecurityTokenExpiry() (time.Time, error)
+}
+
+// SecurityTokenExpiry returns the time from the exp claim of a JWT security token
+func SecurityTokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("security token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, err
+	}
+	if claims.Exp == nil {
+		return time.Time{}, errors.New("security token has no exp claim")
+	}
+
+	exp, err := claims.Exp.Int64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(exp, 0), nil
+}
+
+// securityTokenExpiry returns the expiry of the security token of provider if it implements [SecurityTokenExpirer]
+func securityTokenExpiry(provider common.ConfigurationProvider) (time.Time, error) {
+	if e, ok := provider.(SecurityTokenExpirer); ok {
+		return e.SecurityTokenExpiry()
+	}
+	return time.Time{}, fmt.Errorf("%s does not authenticate with a security token", describeSource(provider, FieldKeyID))
+}
+
+func readSecurityToken(tokenFile *internal.CachedFile, tokenPath string, policy FilePermissionPolicy) (string, error) {
+	tokenPath = internal.ExpandPath(tokenPath)
+	if err := policy.checkFilePermissions(tokenPath); err != nil {
+		return "", err
+	}
+
+	token, err := tokenFile.Read(tokenPath)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// How often a token file that no longer holds a JWT is read before it is taken as changed for good
+const (
+	securityTokenReadAttempts = 5
+	securityTokenRetryDelay   = 20 * time.Millisecond
+)
+
+// securityTokenFile reads a security token file, refreshing the token when it is about to expire
+type securityTokenFile struct {
+	mu   sync.Mutex
+	file internal.CachedFile
+	// jwt is set once the file held a JWT
+	jwt bool
+}
+
+// readToken reads the token in tokenPath and its expiry, which is zero for a token that is not a JWT.
+// Once the file held a JWT, a token that is not one is taken for a file that is being written and read
+// again, and is an error if it stays that way.
+func (f *securityTokenFile) readToken(tokenPath string, policy FilePermissionPolicy) (string, time.Time, error) {
+	for attempt := 1; ; attempt++ {
+		token, err := readSecurityToken(&f.file, tokenPath, policy)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+
+		expiry, err := SecurityTokenExpiry(token)
+		if err == nil {
+			f.jwt = true
+			return token, expiry, nil
+		}
+		if !f.jwt {
+			return token, time.Time{}, nil
+		}
+
+		if attempt == securityTokenReadAttempts {
+			return "", time.Time{}, fmt.Errorf("security token in %s is no longer a JWT: %w", tokenPath, err)
+		}
+		time.Sleep(securityTokenRetryDelay)
+	}
+}
+
+// expiry returns the expiry of the token in tokenPath
+func (f *securityTokenFile) expiry(tokenPath string, policy FilePermissionPolicy) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	token, expiry, err := f.readToken(tokenPath, policy)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if expiry.IsZero() {
+		return SecurityTokenExpiry(token)
+	}
+	return expiry, nil
+}
+
+// token reads the token in tokenPath, refreshing it first if it expires within the refresh margin
+func (f *securityTokenFile) token(tokenPath string, o *options) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	token, expiry, err := f.readToken(tokenPath, o.filePermissions)
+	if err != nil {
+		return "", err
+	}
+	if expiry.IsZero() {
+		// tokens without an expiry claim are used as they are
+		return token, nil
+	}
+
+	if o.tokenRefresh != nil && time.Until(expiry) < o.tokenRefreshMargin {
+		if err = o.tokenRefresh(internal.ExpandPath(tokenPath), expiry); err != nil {
+			return "", fmt.Errorf("could not refresh security token: %w", err)
+		}
+		if token, expiry, err = f.readToken(tokenPath, o.filePermissions); err != nil {
+			return "", err
+		}
+	}
+
+	if !time.Now().Before(expiry) {
+		return "", fmt.Errorf("%w at %s", ErrSecurityTokenExpired, expiry.Format(time.RFC3339))
+	}
+	return token, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/ontariosystems/oci-cli-env-provider"
+)
+
+var _ = Describe("SecurityToken", func() {
+	var (
+		tokenFilePath string
+		env           map[string]string
+	)
+
+	BeforeEach(func() {
+		tokenFilePath = createTempFile([]byte(testJWT(time.Now().Add(time.Hour))))
+		DeferCleanup(os.Remove, tokenFilePath)
+		env = map[string]string{
+			EnvTenancy:           testTenancy,
+			EnvFingerprint:       testFingerprint,
+			EnvAuth:              string(SecurityTokenType),
+			EnvSecurityTokenFile: tokenFilePath,
+		}
+	})
+
+	It("parses the expiry of a token", func() {
+		expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+		Expect(SecurityTokenExpiry(testJWT(expiry))).To(BeTemporally("==", expiry))
+
+		_, err := SecurityTokenExpiry(testSecurityToken)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("exposes the expiry of the token file", func() {
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+		expiry, err := conf.(SecurityTokenExpirer).SecurityTokenExpiry()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expiry).To(BeTemporally("~", time.Now().Add(time.Hour), 2*time.Second))
+	})
+
+	It("exposes the expiry of the token file through DefaultConfigProvider", func() {
+		for key, value := range env {
+			_ = os.Setenv(key, value)
+		}
+
+		expiry, err := DefaultConfigProvider().(SecurityTokenExpirer).SecurityTokenExpiry()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expiry).To(BeTemporally("~", time.Now().Add(time.Hour), 2*time.Second))
+	})
+
+	It("has no expiry without a security token", func() {
+		conf := ComposingConfigProvider(OciCliEnvironmentConfigurationProvider(WithEnvMap(map[string]string{EnvTenancy: testTenancy})))
+		_, err := conf.(SecurityTokenExpirer).SecurityTokenExpiry()
+		Expect(err).To(MatchError(ErrNoKeyId))
+	})
+
+	When("the token has expired", func() {
+		BeforeEach(func() {
+			_ = os.WriteFile(tokenFilePath, []byte(testJWT(time.Now().Add(-time.Minute))), 0600)
+		})
+
+		It("returns ErrSecurityTokenExpired", func() {
+			_, err := OciCliEnvironmentConfigurationProvider(WithEnvMap(env)).KeyID()
+			Expect(err).To(MatchError(ErrSecurityTokenExpired))
+		})
+
+		It("refreshes the token", func() {
+			refreshed := testJWT(time.Now().Add(time.Hour))
+			var refreshedFile string
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithSecurityTokenRefresh(time.Minute, func(tokenFile string, _ time.Time) error {
+				refreshedFile = tokenFile
+				return os.WriteFile(tokenFile, []byte(refreshed), 0600)
+			}))
+
+			Expect(conf.KeyID()).To(Equal("ST$" + refreshed))
+			Expect(refreshedFile).To(Equal(tokenFilePath))
+		})
+
+		It("returns the refresh error", func() {
+			refreshErr := errors.New("refresh failed")
+			conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithSecurityTokenRefresh(time.Minute, func(string, time.Time) error {
+				return refreshErr
+			}))
+
+			_, err := conf.KeyID()
+			Expect(err).To(MatchError(refreshErr))
+		})
+	})
+
+	It("refreshes the token within the margin", func() {
+		calls := 0
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithSecurityTokenRefresh(2*time.Hour, func(string, time.Time) error {
+			calls++
+			return nil
+		}))
+
+		_, err := conf.KeyID()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("does not refresh the token outside the margin", func() {
+		calls := 0
+		conf := OciCliEnvironmentConfigurationProvider(WithEnvMap(env), WithSecurityTokenRefresh(time.Minute, func(string, time.Time) error {
+			calls++
+			return nil
+		}))
+
+		_, err := conf.KeyID()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(BeZero())
+	})
+
+	When("the token file held a JWT", func() {
+		var conf interface{ KeyID() (string, error) }
+
+		BeforeEach(func() {
+			conf = OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			_, err := conf.KeyID()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("does not return a truncated token", func() {
+			token := testJWT(time.Now().Add(time.Hour))
+			_ = os.WriteFile(tokenFilePath, []byte(token[:len(token)/2]), 0600)
+
+			_, err := conf.KeyID()
+			Expect(err).To(MatchError(ContainSubstring("is no longer a JWT")))
+		})
+
+		It("reads the token again until it is a JWT", func() {
+			token := testJWT(time.Now().Add(2 * time.Hour))
+			_ = os.WriteFile(tokenFilePath, []byte(token[:len(token)/2]), 0600)
+			go func() {
+				time.Sleep(30 * time.Millisecond)
+				_ = os.WriteFile(tokenFilePath, []byte(token), 0600)
+			}()
+
+			Expect(conf.KeyID()).To(Equal("ST$" + token))
+		})
+	})
+
+	Context("reading the token file", func() {
+		var conf interface{ KeyID() (string, error) }
+
+		BeforeEach(func() {
+			_ = os.WriteFile(tokenFilePath, []byte(testSecurityToken), 0600)
+			conf = OciCliEnvironmentConfigurationProvider(WithEnvMap(env))
+			Expect(conf.KeyID()).To(Equal("ST$" + testSecurityToken))
+		})
+
+		It("reads the token again when it is rewritten", func() {
+			_ = os.WriteFile(tokenFilePath, []byte("rewritten-"+testSecurityToken), 0600)
+			Expect(conf.KeyID()).To(Equal("ST$rewritten-" + testSecurityToken))
+		})
+
+		It("reads the token again when it is replaced by a rename", func() {
+			replacement := createTempFile([]byte("renamed-" + testSecurityToken))
+			Expect(os.Rename(replacement, tokenFilePath)).To(Succeed())
+			Expect(conf.KeyID()).To(Equal("ST$renamed-" + testSecurityToken))
+		})
+
+		It("keeps the token while the file is unchanged", func() {
+			info, err := os.Stat(tokenFilePath)
+			Expect(err).ToNot(HaveOccurred())
+
+			sameSize := []byte(testSecurityToken)
+			sameSize[0] = 'X'
+			_ = os.WriteFile(tokenFilePath, sameSize, 0600)
+			_ = os.Chtimes(tokenFilePath, info.ModTime(), info.ModTime())
+
+			Expect(conf.KeyID()).To(Equal("ST$" + testSecurityToken))
+		})
+
+		It("does not return an empty token", func() {
+			_ = os.WriteFile(tokenFilePath, nil, 0600)
+			_, err := conf.KeyID()
+			Expect(err).To(MatchError(ContainSubstring("is empty")))
+		})
+	})
+})
+
+func testJWT(expiry time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"%s","exp":%d}`, testUser, expiry.Unix())))
+	return header + "." + payload + ".signature"
+}
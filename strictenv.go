@@ -0,0 +1,146 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// credentialEnvVars are the variables that make the environment authoritative with [WithStrictEnv]
+var credentialEnvVars = []string{EnvTenancy, EnvUser, EnvFingerprint, EnvKeyContent, EnvKeyFile, EnvSecurityTokenFile}
+
+// credentialsSet reports whether any of the credential variables is set
+func (p *ociCliEnvProvider) credentialsSet() bool {
+	for _, envVar := range credentialEnvVars {
+		if _, ok := p.lookupEnv(envVar); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialType is the value of OCI_CLI_AUTH, or else [SecurityTokenType] when OCI_CLI_SECURITY_TOKEN_FILE
+// is set, or else [ApiKeyType]. It decides which variables the credentials need and how the KeyID is built.
+func (p *ociCliEnvProvider) credentialType() common.AuthenticationType {
+	if value, ok := p.lookupEnv(EnvAuth); ok {
+		return common.AuthenticationType(value)
+	}
+	if _, ok := p.lookupEnv(EnvSecurityTokenFile); ok {
+		return SecurityTokenType
+	}
+	return ApiKeyType
+}
+
+// missingEnv returns an [EnvError] for every variable the credentials in the environment need
+// that is not set, or nil if none are missing
+func (p *ociCliEnvProvider) missingEnv() error {
+	required := []string{EnvTenancy}
+	if p.credentialType() == SecurityTokenType {
+		required = append(required, EnvSecurityTokenFile)
+	} else {
+		required = append(required, EnvUser)
+	}
+	if !p.deriveFingerprint {
+		required = append(required, EnvFingerprint)
+	}
+	required = append(required, EnvRegion)
+
+	var errs []error
+	for _, envVar := range required {
+		if _, ok := p.lookupEnv(envVar); !ok {
+			errs = append(errs, &EnvError{envVar})
+		}
+	}
+	_, contentOk := p.lookupEnv(EnvKeyContent)
+	_, fileOk := p.lookupEnv(EnvKeyFile)
+	if !contentOk && !fileOk {
+		errs = append(errs, &EnvError{EnvKeyContent}, &EnvError{EnvKeyFile})
+	}
+	return errors.Join(errs...)
+}
+
+// strictEnvProvider answers every method with the missing variables until the environment is complete
+type strictEnvProvider struct {
+	*ociCliEnvProvider
+}
+
+func (p strictEnvProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
+	if err := p.missingEnv(); err != nil {
+		return nil, err
+	}
+	return p.ociCliEnvProvider.PrivateRSAKey()
+}
+
+// KeyID is built from the security token when the credential type is [SecurityTokenType], and from
+// the user otherwise
+func (p strictEnvProvider) KeyID() (string, error) {
+	if err := p.missingEnv(); err != nil {
+		return "", err
+	}
+	if p.credentialType() == SecurityTokenType {
+		token, err := p.securityToken()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ST$%s", token), nil
+	}
+	return p.ociCliEnvProvider.KeyID()
+}
+
+func (p strictEnvProvider) TenancyOCID() (string, error) {
+	if err := p.missingEnv(); err != nil {
+		return "", err
+	}
+	return p.ociCliEnvProvider.TenancyOCID()
+}
+
+// UserOCID returns "" when the credential type is [SecurityTokenType] and OCI_CLI_USER is not set
+func (p strictEnvProvider) UserOCID() (string, error) {
+	if err := p.missingEnv(); err != nil {
+		return "", err
+	}
+	if _, ok := p.lookupEnv(EnvUser); !ok && p.credentialType() == SecurityTokenType {
+		return "", nil
+	}
+	return p.ociCliEnvProvider.UserOCID()
+}
+
+func (p strictEnvProvider) KeyFingerprint() (string, error) {
+	if err := p.missingEnv(); err != nil {
+		return "", err
+	}
+	return p.ociCliEnvProvider.KeyFingerprint()
+}
+
+func (p strictEnvProvider) Region() (string, error) {
+	if err := p.missingEnv(); err != nil {
+		return "", err
+	}
+	return p.ociCliEnvProvider.Region()
+}
+
+// AuthType follows from the credential type, so it is known even if OCI_CLI_AUTH is not set
+func (p strictEnvProvider) AuthType() (common.AuthConfig, error) {
+	if err := p.missingEnv(); err != nil {
+		return common.AuthConfig{AuthType: common.UnknownAuthenticationType}, err
+	}
+	return authConfig(p.credentialType()), nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 Finvi, Ontario Systems
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocep_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/oracle/oci-go-sdk/v65/common"
+
+	. "github.com/ontariosystems/oci-cli-env-provider"
+)
+
+var _ = Describe("WithStrictEnv", func() {
+	var (
+		configFile     string
+		privateKeyPath string
+		env            map[string]string
+	)
+
+	newProvider := func(opts ...DefaultOption) common.ConfigurationProvider {
+		return NewDefaultConfigProvider(append([]DefaultOption{WithEnvMap(env), WithConfigFile(configFile), WithProfile("ci"), WithStrictEnv()}, opts...)...)
+	}
+
+	missingEnv := func(err error) []string {
+		var missing []string
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				var envErr *EnvError
+				if errors.As(e, &envErr) {
+					missing = append(missing, envErr.EnvVar)
+				}
+			}
+		}
+		return missing
+	}
+
+	BeforeEach(func() {
+		privateKeyPath = createTempFile(testPrivateKeyConf)
+		DeferCleanup(os.Remove, privateKeyPath)
+		configFile = createTempFile([]byte("[DEFAULT]\n[ci]\nuser = " + testUser + "\nfingerprint = " + testFingerprint +
+			"\nkey_file = " + privateKeyPath + "\ntenancy = " + testAltTenancy + "\nregion = " + "us-phoenix-1" + "\n"))
+		DeferCleanup(os.Remove, configFile)
+		env = map[string]string{}
+	})
+
+	It("uses the config file when no credential variable is set", func() {
+		env[EnvRegion] = testRegion
+		env[EnvAuth] = string(ApiKeyType)
+
+		conf := newProvider()
+		Expect(conf.TenancyOCID()).To(Equal(testAltTenancy))
+		Expect(conf.Region()).To(Equal(testRegion))
+	})
+
+	It("lists every missing variable instead of using the config file", func() {
+		env[EnvUser] = testUser
+
+		conf := newProvider()
+		_, err := conf.TenancyOCID()
+		Expect(missingEnv(err)).To(Equal([]string{EnvTenancy, EnvFingerprint, EnvRegion, EnvKeyContent, EnvKeyFile}))
+		_, err = conf.Region()
+		Expect(err).To(HaveOccurred())
+		_, err = conf.AuthType()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("environment variable " + EnvFingerprint + " is not set"))
+	})
+
+	It("does not require a fingerprint that is derived from the key", func() {
+		env[EnvTenancy] = testTenancy
+		env[EnvKeyFile] = privateKeyPath
+
+		_, err := newProvider(WithDerivedFingerprint()).KeyID()
+		Expect(missingEnv(err)).To(Equal([]string{EnvUser, EnvRegion}))
+	})
+
+	It("requires the security token file instead of the user", func() {
+		env[EnvAuth] = string(SecurityTokenType)
+		env[EnvKeyContent] = string(testPrivateKeyConf)
+
+		_, err := newProvider().KeyID()
+		Expect(missingEnv(err)).To(Equal([]string{EnvTenancy, EnvSecurityTokenFile, EnvFingerprint, EnvRegion}))
+	})
+
+	It("follows OCI_CLI_AUTH rather than the security token file", func() {
+		tokenPath := createTempFile([]byte(testJWT(time.Now().Add(time.Hour))))
+		DeferCleanup(os.Remove, tokenPath)
+		env[EnvAuth] = string(ApiKeyType)
+		env[EnvSecurityTokenFile] = tokenPath
+		env[EnvTenancy] = testTenancy
+		env[EnvFingerprint] = testFingerprint
+		env[EnvRegion] = testRegion
+		env[EnvKeyFile] = privateKeyPath
+
+		conf := newProvider()
+		_, err := conf.KeyID()
+		Expect(missingEnv(err)).To(Equal([]string{EnvUser}))
+		_, err = conf.AuthType()
+		Expect(missingEnv(err)).To(Equal([]string{EnvUser}))
+
+		env[EnvUser] = testUser
+		Expect(conf.AuthType()).To(Equal(common.AuthConfig{AuthType: common.UserPrincipal}))
+		Expect(conf.KeyID()).To(Equal(testTenancy + "/" + testUser + "/" + testFingerprint))
+	})
+
+	It("builds the KeyID from the security token when OCI_CLI_AUTH says so", func() {
+		token := testJWT(time.Now().Add(time.Hour))
+		tokenPath := createTempFile([]byte(token))
+		DeferCleanup(os.Remove, tokenPath)
+		env[EnvAuth] = string(SecurityTokenType)
+		env[EnvSecurityTokenFile] = tokenPath
+		env[EnvUser] = testUser
+		env[EnvTenancy] = testTenancy
+		env[EnvFingerprint] = testFingerprint
+		env[EnvRegion] = testRegion
+		env[EnvKeyFile] = privateKeyPath
+
+		Expect(newProvider().KeyID()).To(Equal("ST$" + token))
+	})
+
+	Context("a complete environment", func() {
+		BeforeEach(func() {
+			env[EnvTenancy] = testTenancy
+			env[EnvFingerprint] = testFingerprint
+			env[EnvRegion] = testRegion
+			env[EnvKeyFile] = privateKeyPath
+		})
+
+		It("is valid for an api key", func() {
+			env[EnvUser] = testUser
+
+			conf := newProvider()
+			Expect(common.IsConfigurationProviderValid(conf)).To(BeTrue())
+			Expect(conf.TenancyOCID()).To(Equal(testTenancy))
+			Expect(conf.AuthType()).To(Equal(common.AuthConfig{AuthType: common.UserPrincipal}))
+			Expect(conf.(Resolver).Resolution().ConfigFilePath).To(Equal(configFile))
+		})
+
+		It("is valid for a security token", func() {
+			token := testJWT(time.Now().Add(time.Hour))
+			tokenPath := createTempFile([]byte(token))
+			DeferCleanup(os.Remove, tokenPath)
+			env[EnvSecurityTokenFile] = tokenPath
+
+			conf := newProvider()
+			Expect(common.IsConfigurationProviderValid(conf)).To(BeTrue())
+			Expect(conf.UserOCID()).To(BeEmpty())
+			Expect(conf.KeyID()).To(Equal("ST$" + token))
+			Expect(conf.AuthType()).To(Equal(common.AuthConfig{AuthType: SecurityTokenType}))
+		})
+
+		It("uses OCI_CLI_AUTH", func() {
+			env[EnvUser] = testUser
+			env[EnvAuth] = string(ApiKeyType)
+
+			Expect(newProvider().AuthType()).To(Equal(common.AuthConfig{AuthType: common.UserPrincipal}))
+		})
+	})
+
+	It("still falls back without strict mode", func() {
+		env[EnvUser] = testUser
+
+		conf := NewDefaultConfigProvider(WithEnvMap(env), WithConfigFile(configFile), WithProfile("ci"))
+		Expect(conf.TenancyOCID()).To(Equal(testAltTenancy))
+	})
+})